@@ -1,10 +1,13 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/bank-demo/currency"
 	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/token"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 )
@@ -13,9 +16,42 @@ import (
 // think what will happen when an account be create, initial balance should be 0.
 // because when server recieve those parameters from HTTP request are JSON, should be json tag in struct
 // also valid those input parameter by
+// -------after token------
+// owner is not from request anymore, it is the username in the access token payload,
+// so a user can only create account for the authenticated user.
+// type is checking or savings, it is checking if empty.
 type creatAccountRequest struct {
-	Owner    string `json:"owner" binding:"required"`
-	Currency string `json:"currency" binding:"required,oneof=USD TWD"`
+	Currency string `json:"currency" binding:"required,currency"`
+	Type     string `json:"type" binding:"omitempty,oneof=checking savings"`
+}
+
+// accountResponse is the account object return to client
+// balance is int64 in the minor unit of the currency, e.g. cents,
+// formattedBalance is the decimal string of it for display, e.g. "12.34".
+// availableBalance is the balance minus the money reserved by holds.
+type accountResponse struct {
+	db.Accounts
+	FormattedBalance          string `json:"formattedBalance"`
+	AvailableBalance          int64  `json:"availableBalance"`
+	FormattedAvailableBalance string `json:"formattedAvailableBalance"`
+}
+
+func newAccountResponse(account db.Accounts) accountResponse {
+	available := db.AvailableBalance(account)
+	return accountResponse{
+		Accounts:                  account,
+		FormattedBalance:          currency.Format(account.Balance, account.Currency),
+		AvailableBalance:          available,
+		FormattedAvailableBalance: currency.Format(available, account.Currency),
+	}
+}
+
+func newAccountResponses(accounts []db.Accounts) []accountResponse {
+	responses := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = newAccountResponse(account)
+	}
+	return responses
 }
 
 // implement creatAccount API, gin's HandlFunc take gin.Context as input
@@ -23,7 +59,7 @@ func (server *Server) createAccount(ctx *gin.Context) {
 	var request creatAccountRequest
 	// take request to ShoulBindJson
 	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.Error(badRequest(err))
 		return
 	}
 	// if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -33,20 +69,28 @@ func (server *Server) createAccount(ctx *gin.Context) {
 	// }
 	// if iput parameters are valided correct
 	// create account into DB, use them into CreateAccountParams from account.sql.go
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
-		Owner:    request.Owner,
+		Owner:    authPayload.Username,
 		Currency: request.Currency,
 		Balance:  0,
+		Type:     request.Type,
+	}
+	if arg.Type == "" {
+		arg.Type = db.AccountTypeChecking
 	}
 	// pass arg to CreatAccount() from account.sql.go, that will return Account and error
 	account, err := server.store.CreateAccount(ctx, arg)
 	if err != nil {
-		// the error is happen at inserting arg to DB, so use http.StatusInternalServerError
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// currency must be in the currencies table, owner must be an existed user,
+		// and one user can only have one account of each type for each currency.
+		// those are checked by constraints in db, errorHandler maps the unknown currency or owner to 422,
+		// and the duplicated account to 409
+		ctx.Error(err)
 		return
 	}
 	// if no error,send StatusOK and create account object to client
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 
 }
 
@@ -61,59 +105,203 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	// use ShouldBindUri to bind uri
 	if err := ctx.ShouldBindUri(&request); err != nil {
 		// return 400 code with JSON format to client
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.Error(badRequest(err))
 		return
 	}
 	// if no error, connect the db and get info of account by method server.store.GetAccount
 	account, err := server.store.GetAccount(ctx, request.ID)
 	if err != nil {
-		// if the account not existed in db
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		// other error between server and db operation
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// db.ErrAccountNotFound becomes 404, other error between server and db becomes 500
+		ctx.Error(err)
+		return
+	}
+	// a user can only get the account owned by the user
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
 		return
 	}
 	// if no error, return account in JSON format to client
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 
 }
 
 // the url is /accounts, didn't take parameter from uri
 // On Postman, use Query param -- key-value as input
 // Page_ID : index of page number when query
-// page_Size: number of records on 1 page - min = 5, max = 10
+// page_Size: number of records on 1 page - max is MaxPageSize in config
 // Page_ID, Page_Size have to use tag "form"
+// -------after cursor pagination------
+// page_id is optional now, the client can use the cursor instead, see pagination.go
 type listAccountRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	pageRequest
 }
 
 func (server *Server) listAccount(ctx *gin.Context) {
 	var request listAccountRequest
 	//use ShouldBindQuery to tell gin to get data from query string
 	if err := ctx.ShouldBindQuery(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.Error(badRequest(err))
+		return
+	}
+	page, err := server.newPage(request.pageRequest)
+	if err != nil {
+		ctx.Error(badRequest(err))
 		return
 	}
 	// if no error after get parameter from request to connect to db ,
-	// using ListAccount to query page of account records from db
+	// using ListAccountsPage to query page of account records from db
 	// think the SQL syntax in account.sql
-	// Limit is PageSize, Offet is number of records that db should skip.
-	arg := db.ListAccountsParams{
-		Limit:  request.PageSize,
-		Offset: (request.PageID - 1) * request.PageSize,
+	// only list the accounts owned by the authenticated user
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListAccountsPageParams{
+		Owner:          authPayload.Username,
+		AfterCreatedAt: page.after.CreatedAt,
+		AfterID:        page.after.ID,
+		PageLimit:      page.limit(),
+		PageOffset:     page.offset,
 	}
 
-	accounts, err := server.store.ListAccounts(ctx, arg)
+	accounts, err := server.store.ListAccountsPage(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.Error(err)
 		return
 	}
 
+	n, nextCursor := page.next(len(accounts), func(i int) pageCursor {
+		return pageCursor{CreatedAt: accounts[i].CreatedAt, ID: accounts[i].ID}
+	})
 	// get list of accounts success, and return to client
-	ctx.JSON(http.StatusOK, accounts)
+	ctx.JSON(http.StatusOK, page.response(newAccountResponses(accounts[:n]), nextCursor))
+
+}
+
+// updateAccountRequest contains the fields which can be changed by the owner
+// balance, currency and status are not here, they are changed by other APIs.
+// all fields are optional, only the provided fields are updated.
+type updateAccountRequest struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=64"`
+}
+
+// implement updateAccount API, PATCH /accounts/:id
+func (server *Server) updateAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request updateAccountRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	if request.Nickname != nil {
+		var err error
+		account, err = server.store.UpdateAccountNickname(ctx, db.UpdateAccountNicknameParams{
+			ID:       account.ID,
+			Nickname: *request.Nickname,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
 
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// implement freezeAccount API, a frozen account can not send or receive money
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, db.AccountStatusFrozen)
+}
+
+// implement unfreezeAccount API, the account becomes active again
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, db.AccountStatusActive)
+}
+
+// implement closeAccount API, only an account with zero balance can be closed
+// accounts are never deleted, because the entries and transfers still refer to them.
+func (server *Server) closeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, db.AccountStatusClosed)
+}
+
+// changeAccountStatus changes the status of the account in uri
+// status code:
+// 401 - account is not owned by the authenticated user
+// 404 - account not found in db
+// 409 - status can not be changed, e.g. the account is closed, or its balance is not zero when closing
+func (server *Server) changeAccountStatus(ctx *gin.Context, status string) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	if _, valid := server.ownAccount(ctx, request.ID); !valid {
+		return
+	}
+	// lock the account, so its status and balance can not be changed by a transfer at the same time
+	var account db.Accounts
+	err := server.store.ExecTx(ctx, nil, func(q db.Querier) error {
+		var err error
+		account, err = q.GetAccountForUpdate(ctx, request.ID)
+		if err != nil {
+			// q is the Queries of the transaction, sql.ErrNoRows is not translated by the store
+			return db.TranslateNotFound(err, db.ErrAccountNotFound)
+		}
+
+		if !db.CanChangeAccountStatus(account.Status, status) {
+			return fmt.Errorf("%w: account [%d] from %s to %s", db.ErrInvalidStatusChange, account.ID, account.Status, status)
+		}
+		// also checked by the accounts_closed_zero_balance constraint
+		if status == db.AccountStatusClosed && account.Balance != 0 {
+			return fmt.Errorf("%w: account [%d] balance is %d", db.ErrAccountNotEmpty, account.ID, account.Balance)
+		}
+		// the holds must be captured or released before closing
+		if status == db.AccountStatusClosed && account.HeldAmount != 0 {
+			return fmt.Errorf("%w: account [%d] has %d on hold", db.ErrAccountNotEmpty, account.ID, account.HeldAmount)
+		}
+		// the accrued interest must be paid before closing
+		if status == db.AccountStatusClosed && account.AccruedInterest != 0 {
+			return fmt.Errorf("%w: account [%d] has %d interest not paid", db.ErrAccountNotEmpty, account.ID, account.AccruedInterest)
+		}
+
+		account, err = q.SetAccountStatus(ctx, db.SetAccountStatusParams{
+			ID:     account.ID,
+			Status: status,
+		})
+		return err
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// ownAccount gets the account and checks it is owned by the authenticated user
+// if not, the error is already added to ctx, so the caller just need to return.
+func (server *Server) ownAccount(ctx *gin.Context, accountID int64) (db.Accounts, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		ctx.Error(err)
+		return account, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
+		return account, false
+	}
+	return account, true
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// getBalanceRequest contains the time of the historical balance
+// as_of is a date, e.g. 2021-03-31, for the balance at the end of the day in UTC,
+// or a time in RFC 3339, e.g. 2021-03-31T12:00:00+08:00, for the balance at the time.
+type getBalanceRequest struct {
+	AsOf string `form:"as_of" binding:"required"`
+}
+
+// asOf returns the time of the balance, the entries before the time are included
+func (request getBalanceRequest) asOf() (time.Time, error) {
+	if day, err := time.Parse("2006-01-02", request.AsOf); err == nil {
+		return day.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339, request.AsOf)
+	if err != nil {
+		return t, errors.New("as_of must be a date YYYY-MM-DD or a time in RFC 3339")
+	}
+	return t.UTC(), nil
+}
+
+// balanceResponse is the balance of the account at the time AsOf
+type balanceResponse struct {
+	AccountID        int64     `json:"accountID"`
+	Currency         string    `json:"currency"`
+	AsOf             time.Time `json:"asOf"`
+	Balance          int64     `json:"balance"`
+	FormattedBalance string    `json:"formattedBalance"`
+}
+
+// implement getBalance API, GET /accounts/:id/balance
+// the balance is derived from the entries before as_of, with the help of the balance snapshots.
+// status code:
+// 400 - as_of is missing or invalid
+// 401 - account is not owned by the authenticated user
+// 404 - account not found in db
+func (server *Server) getBalance(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request getBalanceRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	asOf, err := request.asOf()
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	balance, err := server.store.GetAccountBalanceBefore(ctx, db.GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    asOf,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, balanceResponse{
+		AccountID:        account.ID,
+		Currency:         account.Currency,
+		AsOf:             asOf,
+		Balance:          balance,
+		FormattedBalance: currency.Format(balance, account.Currency),
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type cashRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// cashTxRequest is the request of deposit and withdrawal with the account in uri
+// it is used to compute the hash of the idempotency key,
+// so the same key can not be reused for another account or the other direction.
+type cashTxRequest struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	Deposit   bool  `json:"deposit"`
+}
+
+// cashTxResponse is db.CashTxResult with formatted amounts
+type cashTxResponse struct {
+	Transfer transferResponse `json:"transfer"`
+	Account  accountResponse  `json:"account"`
+	Entry    entryResponse    `json:"entry"`
+}
+
+func newCashTxResponse(result db.CashTxResult) cashTxResponse {
+	code := result.Account.Currency
+	return cashTxResponse{
+		Transfer: newTransferResponse(result.Transfer, code, code),
+		Account:  newAccountResponse(result.Account),
+		Entry:    newEntryResponses([]db.Entries{result.Entry}, code)[0],
+	}
+}
+
+// implement createDeposit API, POST /accounts/:id/deposits
+func (server *Server) createDeposit(ctx *gin.Context) {
+	server.cashTx(ctx, true)
+}
+
+// implement createWithdrawal API, POST /accounts/:id/withdrawals
+func (server *Server) createWithdrawal(ctx *gin.Context) {
+	server.cashTx(ctx, false)
+}
+
+// cashTx puts money into or takes money out of the account in uri, through the cash account of the bank
+// Idempotency-Key header is supported like createTransfer.
+// status code:
+// 401 - account is not owned by the authenticated user
+// 404 - account not found in db
+// 409 - balance is not enough for the withdrawal, or the account is frozen or closed
+// 422 - the withdrawal exceeds the transfer limits of the account
+func (server *Server) cashTx(ctx *gin.Context, deposit bool) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request cashRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	idem, ok := newIdempotentRequest(ctx, authPayload.Username, cashTxRequest{
+		AccountID: uri.ID,
+		Amount:    request.Amount,
+		Deposit:   deposit,
+	})
+	if !ok {
+		return
+	}
+	if idem != nil && server.replay(ctx, idem) {
+		return
+	}
+
+	if _, valid := server.ownAccount(ctx, uri.ID); !valid {
+		return
+	}
+
+	arg := db.CashTxParams{
+		AccountID: uri.ID,
+		Amount:    request.Amount,
+	}
+	if idem != nil {
+		arg.AfterTx = func(q db.Querier, result db.CashTxResult) error {
+			return idem.save(ctx, q, http.StatusOK, newCashTxResponse(result))
+		}
+	}
+
+	txFunc := server.store.WithdrawTx
+	if deposit {
+		txFunc = server.store.DepositTx
+	}
+	result, err := txFunc(ctx, arg)
+	if err != nil {
+		if idem != nil && isDuplicateKeyError(err) && server.replay(ctx, idem) {
+			return
+		}
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCashTxResponse(result))
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// direction of the money, from the view of the account in uri
+const (
+	directionIncoming = "in"
+	directionOutgoing = "out"
+)
+
+// listHistoryRequest contains the filters of entries and transfers of an account
+// all filters are optional:
+// direction: in or out, both directions are listed if it is empty
+// min_amount / max_amount: the range of amount, the amount of entry is compared by its absolute value
+// start_date / end_date: e.g. 2021-10-01, both days are included
+type listHistoryRequest struct {
+	pageRequest
+	Direction string    `form:"direction" binding:"omitempty,oneof=in out"`
+	MinAmount int64     `form:"min_amount" binding:"min=0"`
+	MaxAmount int64     `form:"max_amount" binding:"min=0"`
+	StartDate time.Time `form:"start_date" time_format:"2006-01-02" time_utc:"1"`
+	EndDate   time.Time `form:"end_date" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// historyFilter is the listHistoryRequest with default values filled in
+type historyFilter struct {
+	incoming  bool
+	outgoing  bool
+	minAmount int64
+	maxAmount int64
+	startTime time.Time
+	endTime   time.Time
+}
+
+func (request listHistoryRequest) filter() (historyFilter, error) {
+	filter := historyFilter{
+		incoming:  request.Direction != directionOutgoing,
+		outgoing:  request.Direction != directionIncoming,
+		minAmount: request.MinAmount,
+		maxAmount: request.MaxAmount,
+		startTime: request.StartDate,
+		endTime:   request.EndDate,
+	}
+
+	if request.MaxAmount == 0 {
+		filter.maxAmount = math.MaxInt64
+	}
+	if filter.minAmount > filter.maxAmount {
+		return filter, errors.New("min_amount must not be greater than max_amount")
+	}
+	// end_date is included, so the end time is the beginning of the next day
+	if request.EndDate.IsZero() {
+		filter.endTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	} else {
+		filter.endTime = request.EndDate.AddDate(0, 0, 1)
+	}
+	if !filter.startTime.Before(filter.endTime) {
+		return filter, errors.New("start_date must not be after end_date")
+	}
+	return filter, nil
+}
+
+// entryResponse is the entry object return to client, amount is formatted in the currency of the account
+// db.Entries has sql.NullInt64 transfer ID, TransferID replaces it in JSON, it is null for the old entries.
+type entryResponse struct {
+	db.Entries
+	TransferID      *int64 `json:"transferID"`
+	FormattedAmount string `json:"formattedAmount"`
+}
+
+func newEntryResponses(entries []db.Entries, code string) []entryResponse {
+	responses := make([]entryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = entryResponse{
+			Entries:         entry,
+			FormattedAmount: currency.Format(entry.Amount, code),
+		}
+		if entry.TransferID.Valid {
+			responses[i].TransferID = &entries[i].TransferID.Int64
+		}
+	}
+	return responses
+}
+
+// implement listEntries API, GET /accounts/:id/entries
+// a user can only list the entries of the account owned by the user
+func (server *Server) listEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request listHistoryRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	filter, err := request.filter()
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	page, err := server.newPage(request.pageRequest)
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	entries, err := server.store.ListAccountEntries(ctx, db.ListAccountEntriesParams{
+		AccountID:      account.ID,
+		Incoming:       filter.incoming,
+		Outgoing:       filter.outgoing,
+		MinAmount:      filter.minAmount,
+		MaxAmount:      filter.maxAmount,
+		StartTime:      filter.startTime,
+		EndTime:        filter.endTime,
+		AfterCreatedAt: page.after.CreatedAt,
+		AfterID:        page.after.ID,
+		PageLimit:      page.limit(),
+		PageOffset:     page.offset,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	n, nextCursor := page.next(len(entries), func(i int) pageCursor {
+		return pageCursor{CreatedAt: entries[i].CreatedAt, ID: entries[i].ID}
+	})
+	ctx.JSON(http.StatusOK, page.response(newEntryResponses(entries[:n], account.Currency), nextCursor))
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// handlers don't write the error response by themselves anymore,
+// they call ctx.Error(err) and return, then errorHandler writes the response.
+// every error response has the same schema:
+//   {"error": {"code": "account_not_found", "message": "account not found"}}
+// the error of transfer limits also has the name and the value of the limit:
+//   {"error": {"code": "transfer_limit_exceeded", "message": "...", "limit": "daily_amount", "max": 100000}}
+// code is stable and can be checked by client, message is for human.
+
+// apiError is an error raised by the handler with its own status code and error code,
+// e.g. invalid request parameters or the user is not authorized.
+type apiError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+func newAPIError(status int, code string, err error) *apiError {
+	return &apiError{status: status, code: code, err: err}
+}
+
+// badRequest is used when the input parameters are invalid
+func badRequest(err error) *apiError {
+	return newAPIError(http.StatusBadRequest, "invalid_request", err)
+}
+
+// unauthorized is used when the token is invalid, or the user doesn't own the resource
+func unauthorized(err error) *apiError {
+	return newAPIError(http.StatusUnauthorized, "unauthorized", err)
+}
+
+// dbErrors maps the typed errors of db package to status code and error code
+var dbErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{db.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+	{db.ErrRecordNotFound, http.StatusNotFound, "not_found"},
+	{db.ErrInsufficientFunds, http.StatusConflict, "insufficient_funds"},
+	{db.ErrCurrencyMismatch, http.StatusUnprocessableEntity, "currency_mismatch"},
+	{db.ErrInvalidExchangeRate, http.StatusUnprocessableEntity, "invalid_exchange_rate"},
+	{db.ErrInvalidInterestRate, http.StatusUnprocessableEntity, "invalid_interest_rate"},
+	{db.ErrInvalidFeeRule, http.StatusUnprocessableEntity, "invalid_fee_rule"},
+	{db.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{db.ErrUnsupportedCurrency, http.StatusUnprocessableEntity, "unsupported_currency"},
+	{db.ErrUniqueViolation, http.StatusConflict, "already_exists"},
+	// the request refers to a record which doesn't exist, e.g. the owner of a new account
+	{db.ErrForeignKeyViolation, http.StatusUnprocessableEntity, "invalid_reference"},
+	{db.ErrAccountNotActive, http.StatusConflict, "account_not_active"},
+	{db.ErrAccountNotEmpty, http.StatusConflict, "account_not_empty"},
+	{db.ErrInvalidStatusChange, http.StatusConflict, "invalid_status_change"},
+	{db.ErrHoldNotFound, http.StatusNotFound, "hold_not_found"},
+	{db.ErrHoldNotActive, http.StatusConflict, "hold_not_active"},
+	{db.ErrScheduledTransferNotFound, http.StatusNotFound, "scheduled_transfer_not_found"},
+	{db.ErrInvalidSchedule, http.StatusBadRequest, "invalid_schedule"},
+	{db.ErrTransferLimitExceeded, http.StatusUnprocessableEntity, "transfer_limit_exceeded"},
+}
+
+// errorHandler creates a gin middleware which writes the last error of the handler to client
+// the original error is kept in ctx.Errors, so it is still printed by gin logger.
+func errorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+		status, response := errorResponse(last.Err)
+		ctx.JSON(status, response)
+	}
+}
+
+// errorResponse returns the status code and the JSON body of err
+// details of 5xx errors are hidden, because they may contain the sql or other internal information.
+func errorResponse(err error) (int, gin.H) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.status, errorBody(apiErr.code, apiErr.Error())
+	}
+
+	err = db.TranslateError(err)
+	for _, e := range dbErrors {
+		if errors.Is(err, e.err) {
+			body := errorBody(e.code, publicMessage(err, e.err))
+			// the client can tell which limit is exceeded, e.g. "limit": "daily_amount"
+			var limitErr *db.TransferLimitError
+			if errors.As(err, &limitErr) {
+				body["error"].(gin.H)["limit"] = limitErr.Limit
+				body["error"].(gin.H)["max"] = limitErr.Max
+			}
+			return e.status, body
+		}
+	}
+
+	return http.StatusInternalServerError, errorBody("internal_error", "internal server error")
+}
+
+func errorBody(code string, message string) gin.H {
+	return gin.H{"error": gin.H{
+		"code":    code,
+		"message": message,
+	}}
+}
+
+// publicMessage returns the message of err which is safe to send to client
+// if err comes from postgres, only the message of the typed error is returned,
+// otherwise err is created by handler, e.g. "account [1] currency mismatch: USD vs TWD"
+func publicMessage(err error, kind error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) || errors.Is(err, sql.ErrNoRows) {
+		return kind.Error()
+	}
+	return err.Error()
+}
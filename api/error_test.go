@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+type errorResponseBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Limit   string `json:"limit"`
+		Max     int64  `json:"max"`
+	} `json:"error"`
+}
+
+func TestErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+		limit   string
+	}{
+		{
+			name:    "BadRequest",
+			err:     badRequest(errors.New("invalid id")),
+			status:  http.StatusBadRequest,
+			code:    "invalid_request",
+			message: "invalid id",
+		},
+		{
+			name:    "Unauthorized",
+			err:     unauthorized(errors.New("invalid token")),
+			status:  http.StatusUnauthorized,
+			code:    "unauthorized",
+			message: "invalid token",
+		},
+		{
+			name:    "AccountNotFound",
+			err:     db.ErrAccountNotFound,
+			status:  http.StatusNotFound,
+			code:    "account_not_found",
+			message: "account not found",
+		},
+		{
+			// sql.ErrNoRows is translated, and the message of driver is hidden
+			name:    "NoRows",
+			err:     sql.ErrNoRows,
+			status:  http.StatusNotFound,
+			code:    "not_found",
+			message: "record not found",
+		},
+		{
+			name:    "CurrencyMismatch",
+			err:     fmt.Errorf("%w: account [1] USD vs TWD", db.ErrCurrencyMismatch),
+			status:  http.StatusUnprocessableEntity,
+			code:    "currency_mismatch",
+			message: "currency mismatch: account [1] USD vs TWD",
+		},
+		{
+			name:    "InsufficientFunds",
+			err:     db.TranslateError(&pq.Error{Code: db.CheckViolation, Constraint: "accounts_balance_limit"}),
+			status:  http.StatusConflict,
+			code:    "insufficient_funds",
+			message: "insufficient funds",
+		},
+		{
+			name:    "UniqueViolation",
+			err:     &pq.Error{Code: db.UniqueViolation, Message: "duplicate key value violates unique constraint"},
+			status:  http.StatusConflict,
+			code:    "already_exists",
+			message: "record already exists",
+		},
+		{
+			name:    "ForeignKeyViolation",
+			err:     &pq.Error{Code: db.ForeignKeyViolation, Constraint: "accounts_owner_fkey"},
+			status:  http.StatusUnprocessableEntity,
+			code:    "invalid_reference",
+			message: "referenced record doesn't exist",
+		},
+		{
+			name:    "TransferLimitExceeded",
+			err:     &db.TransferLimitError{AccountID: 1, Limit: db.TransferLimitDailyAmount, Max: 100000},
+			status:  http.StatusUnprocessableEntity,
+			code:    "transfer_limit_exceeded",
+			message: "transfer limit exceeded: daily_amount of account [1] is 100000",
+			limit:   "daily_amount",
+		},
+		{
+			name:    "InvalidExchangeRate",
+			err:     fmt.Errorf("%w: %q", db.ErrInvalidExchangeRate, "-1"),
+			status:  http.StatusUnprocessableEntity,
+			code:    "invalid_exchange_rate",
+			message: `invalid exchange rate: "-1"`,
+		},
+		{
+			name:    "InvalidInterestRate",
+			err:     fmt.Errorf("%w: %q", db.ErrInvalidInterestRate, "abc"),
+			status:  http.StatusUnprocessableEntity,
+			code:    "invalid_interest_rate",
+			message: `invalid interest rate: "abc"`,
+		},
+		{
+			name:    "InvalidFeeRule",
+			err:     fmt.Errorf("%w: rule [%d] of %s", db.ErrInvalidFeeRule, 1, "USD"),
+			status:  http.StatusUnprocessableEntity,
+			code:    "invalid_fee_rule",
+			message: "invalid fee rule: rule [1] of USD",
+		},
+		{
+			// details of internal error should never be sent to client
+			name:    "InternalError",
+			err:     fmt.Errorf("select * from accounts: %w", sql.ErrConnDone),
+			status:  http.StatusInternalServerError,
+			code:    "internal_error",
+			message: "internal server error",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(errorHandler())
+			router.GET("/error", func(ctx *gin.Context) {
+				ctx.Error(tc.err)
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/error", nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+
+			var body errorResponseBody
+			err = json.Unmarshal(recorder.Body.Bytes(), &body)
+			require.NoError(t, err)
+			require.Equal(t, tc.code, body.Error.Code)
+			require.Equal(t, tc.message, body.Error.Message)
+			require.Equal(t, tc.limit, body.Error.Limit)
+		})
+	}
+}
+
+func TestErrorHandlerWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(errorHandler())
+	// the handler already writes the response, errorHandler should not write again
+	router.GET("/error", func(ctx *gin.Context) {
+		ctx.Error(errors.New("logged only"))
+		ctx.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/error", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"ok":true}`, recorder.Body.String())
+}
+
+// requireErrorCode checks the code in the error response
+func requireErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, code string) {
+	var body errorResponseBody
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+	require.NoError(t, err)
+	require.Equal(t, code, body.Error.Code)
+}
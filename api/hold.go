@@ -0,0 +1,271 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// a hold works like the authorization of a card payment:
+// the owner of the account places a hold for the to account, the money is reserved but not moved,
+// then the owner of the to account captures it into a transfer, or releases it.
+// a hold which is not captured before it expires is released by scheduler.HoldExpirer in the server process,
+// or by the owner of the account right after it expires.
+
+const (
+	defaultHoldDuration = 7 * 24 * time.Hour
+	maxHoldDuration     = 30 * 24 * time.Hour
+)
+
+// placeHoldRequest to store place hold request
+// expires_in is the lifetime of the hold in seconds, defaultHoldDuration is used if it is empty.
+type placeHoldRequest struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+	ExpiresIn   int64 `json:"expires_in" binding:"omitempty,min=1"`
+}
+
+// holdResponse is the hold object return to client
+// db.Holds has sql.NullInt64 transfer ID, it is null before the hold is captured.
+type holdResponse struct {
+	ID              int64     `json:"id"`
+	AccountID       int64     `json:"accountID"`
+	ToAccountID     int64     `json:"toAccountID"`
+	Amount          int64     `json:"amount"`
+	FormattedAmount string    `json:"formattedAmount"`
+	Status          string    `json:"status"`
+	TransferID      *int64    `json:"transferID"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+func newHoldResponse(hold db.Holds, code string) holdResponse {
+	response := holdResponse{
+		ID:              hold.ID,
+		AccountID:       hold.AccountID,
+		ToAccountID:     hold.ToAccountID,
+		Amount:          hold.Amount,
+		FormattedAmount: currency.Format(hold.Amount, code),
+		Status:          hold.Status,
+		ExpiresAt:       hold.ExpiresAt,
+		CreatedAt:       hold.CreatedAt,
+		UpdatedAt:       hold.UpdatedAt,
+	}
+	if hold.TransferID.Valid {
+		response.TransferID = &hold.TransferID.Int64
+	}
+	return response
+}
+
+// captureHoldResponse is db.CaptureHoldTxResult with formatted amounts
+type captureHoldResponse struct {
+	transferTxResponse
+	Hold holdResponse `json:"hold"`
+}
+
+// implement placeHold API, POST /accounts/:id/holds
+// status code:
+// 400 - input parameters are invalid
+// 401 - account is not owned by the authenticated user
+// 404 - account or to account not found in db
+// 409 - available balance is not enough, or one of the accounts is frozen or closed
+// 422 - currency of the to account is different
+func (server *Server) placeHold(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request placeHoldRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	duration := defaultHoldDuration
+	if request.ExpiresIn > 0 {
+		duration = time.Duration(request.ExpiresIn) * time.Second
+	}
+	if duration > maxHoldDuration {
+		ctx.Error(badRequest(errors.New("expires_in is too long")))
+		return
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if _, valid := server.validAccount(ctx, request.ToAccountID, account.Currency); !valid {
+		return
+	}
+
+	hold, err := server.store.PlaceHoldTx(ctx, db.PlaceHoldTxParams{
+		AccountID:   account.ID,
+		ToAccountID: request.ToAccountID,
+		Amount:      request.Amount,
+		ExpiresAt:   time.Now().Add(duration),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHoldResponse(hold, account.Currency))
+}
+
+type getHoldRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// implement getHold API, GET /holds/:id
+// the owners of both accounts can get the hold
+func (server *Server) getHold(ctx *gin.Context) {
+	var request getHoldRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	hold, err := server.store.GetHold(ctx, request.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range []int64{hold.AccountID, hold.ToAccountID} {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if account.Owner == authPayload.Username {
+			// both accounts have the same currency
+			ctx.JSON(http.StatusOK, newHoldResponse(hold, account.Currency))
+			return
+		}
+	}
+
+	err = errors.New("hold doesn't belong to the authenticated user")
+	ctx.Error(unauthorized(err))
+}
+
+// captureHoldRequest to store capture hold request
+// amount is optional, the whole held amount is captured if it is empty.
+type captureHoldRequest struct {
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// implement captureHold API, POST /holds/:id/capture
+// only the owner of the to account can capture the hold, the money is transferred to the to account.
+// status code:
+// 401 - to account is not owned by the authenticated user
+// 404 - hold not found in db
+// 409 - hold is already captured, released or expired, or one of the accounts is frozen or closed
+// 400 - amount is more than the held amount
+func (server *Server) captureHold(ctx *gin.Context) {
+	var uri getHoldRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request captureHoldRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	hold, valid := server.receivedHold(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: request.Amount,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, captureHoldResponse{
+		transferTxResponse: newTransferTxResponse(result.TransferTxResult),
+		Hold:               newHoldResponse(result.Hold, result.FromAccount.Currency),
+	})
+}
+
+// implement releaseHold API, POST /holds/:id/release
+// the owner of the to account can release the hold at any time,
+// the owner of the account can release it after it expires, so the money doesn't wait for the next expiry run.
+// status code:
+// 401 - the hold can not be released by the authenticated user
+// 409 - hold is already captured, released or expired
+func (server *Server) releaseHold(ctx *gin.Context) {
+	var uri getHoldRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	hold, err := server.store.GetHold(ctx, uri.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	account, err := server.store.GetAccount(ctx, hold.AccountID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	toAccount, err := server.store.GetAccount(ctx, hold.ToAccountID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	expired := !hold.ExpiresAt.After(time.Now())
+	if toAccount.Owner != authPayload.Username && !(expired && account.Owner == authPayload.Username) {
+		err := errors.New("hold can only be released by the owner of the to account, or by the owner of the account after it expires")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	hold, err = server.store.ReleaseHoldTx(ctx, hold.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newHoldResponse(hold, account.Currency))
+}
+
+// receivedHold gets the hold and checks its to account is owned by the authenticated user
+// if not, the error is already added to ctx, so the caller just need to return.
+func (server *Server) receivedHold(ctx *gin.Context, holdID int64) (db.Holds, bool) {
+	hold, err := server.store.GetHold(ctx, holdID)
+	if err != nil {
+		ctx.Error(err)
+		return hold, false
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, hold.ToAccountID)
+	if err != nil {
+		ctx.Error(err)
+		return hold, false
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if toAccount.Owner != authPayload.Username {
+		err := errors.New("to account of the hold doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
+		return hold, false
+	}
+	return hold, true
+}
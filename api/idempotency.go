@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// client may retry a request on flaky network, e.g. timeout after the transfer is done.
+// with the same Idempotency-Key header, the retried request gets the saved response,
+// and the money will not be moved twice.
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotentRequest contains the idempotency key of a request,
+// and the hash of the request body, to check the key is not reused for a different request.
+// the key is scoped by username, so different users can use the same key.
+type idempotentRequest struct {
+	username string
+	key      string
+	hash     string
+}
+
+// newIdempotentRequest reads the Idempotency-Key header of the request
+// if the header is not provided, it returns nil, and the request is handled as usual.
+// if the header is invalid, the error is already added to ctx, and ok is false.
+func newIdempotentRequest(ctx *gin.Context, username string, request interface{}) (idem *idempotentRequest, ok bool) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if len(key) == 0 {
+		return nil, true
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		err := fmt.Errorf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)
+		ctx.Error(badRequest(err))
+		return nil, false
+	}
+	// the request has already been bound to struct, marshal it again to get a stable hash
+	data, err := json.Marshal(request)
+	if err != nil {
+		ctx.Error(err)
+		return nil, false
+	}
+	sum := sha256.Sum256(data)
+
+	idem = &idempotentRequest{
+		username: username,
+		key:      key,
+		hash:     hex.EncodeToString(sum[:]),
+	}
+	return idem, true
+}
+
+// replay writes the saved response of the idempotency key to the client
+// it returns false if the key is never used before, so the request should be handled as usual.
+// status code:
+// 422 - the key is reused with a different request body
+func (server *Server) replay(ctx *gin.Context, idem *idempotentRequest) bool {
+	saved, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: idem.username,
+		Key:      idem.key,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false
+		}
+		ctx.Error(err)
+		return true
+	}
+
+	if saved.RequestHash != idem.hash {
+		err := fmt.Errorf("%s [%s] is already used by a different request", idempotencyKeyHeader, idem.key)
+		ctx.Error(newAPIError(http.StatusUnprocessableEntity, "idempotency_key_reused", err))
+		return true
+	}
+
+	ctx.Data(int(saved.ResponseStatus), "application/json; charset=utf-8", saved.ResponseBody)
+	return true
+}
+
+// save stores the response of the request with its idempotency key
+// q should be the Querier of the transaction which moves the money,
+// so the key is saved if and only if the money is moved.
+func (idem *idempotentRequest) save(ctx context.Context, q db.Querier, status int, response interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
+		Username:       idem.username,
+		Key:            idem.key,
+		RequestHash:    idem.hash,
+		ResponseStatus: int32(status),
+		ResponseBody:   body,
+	})
+	return err
+}
+
+// isDuplicateKeyError reports whether another request with the same idempotency key
+// has been committed at the same time, then the saved response can be replayed.
+func isDuplicateKeyError(err error) bool {
+	return errors.Is(err, db.ErrUniqueViolation)
+}
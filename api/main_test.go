@@ -3,10 +3,28 @@ package api
 import (
 	"os"
 	"testing"
+	"time"
 
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/util"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
 )
 
+// newTestServer creates a server with a random token key for testing
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		MaxPageSize:         10,
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	return server
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
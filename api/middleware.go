@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	// the verified payload is stored in gin.Context with this key,
+	// handlers use it to know who is calling the API
+	authorizationPayloadKey = "authorization_payload"
+)
+
+// authMiddleware creates a gin middleware for authorization
+// the header should look like: Authorization: Bearer <token>
+// if the token is invalid, abort the request with 401 and the handler will not be called.
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		// split the header by space, should get 2 parts: type and token
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) < 2 {
+			err := errors.New("invalid authorization header format")
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		accessToken := fields[1]
+		payload, err := tokenMaker.VerifyToken(accessToken)
+		if err != nil {
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+		// the refresh token lives much longer than the access token and is checked against its session
+		// only in renewAccessToken, so it must not be accepted here.
+		// a stolen refresh token becomes useless after its session is blocked.
+		if payload.Type != token.TokenTypeAccess {
+			err := errors.New("token is not an access token")
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
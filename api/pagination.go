@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// list APIs are paginated by cursor:
+// the first page is requested without cursor, and the response contains next_cursor,
+// the client puts it in the cursor query parameter to get the next page,
+// next_cursor is empty on the last page.
+// the records are ordered by (created_at, id), so the pages don't skip or repeat
+// records when new records are inserted between the requests.
+//
+// old clients with page_id still get the records by offset, and the response is the list as before.
+
+// defaultPageSize is used when page_size is not provided
+const defaultPageSize = 10
+
+// pageRequest contains the pagination query parameters of the list APIs
+type pageRequest struct {
+	PageID   int32  `form:"page_id" binding:"omitempty,min=1"`
+	PageSize int32  `form:"page_size" binding:"omitempty,min=1"`
+	Cursor   string `form:"cursor"`
+}
+
+// listResponse is the response of the list APIs with cursor
+type listResponse struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+// pageCursor is the position of the last record of a page
+// it is encoded as an opaque token, client should not depend on its content.
+type pageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"i"`
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	var cursor pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// page is the pageRequest with default values filled in
+type page struct {
+	// legacy is true if the client uses page_id
+	legacy bool
+	size   int32
+	offset int32
+	after  pageCursor
+}
+
+// newPage checks the pageRequest, page size can not be larger than the MaxPageSize in config
+func (server *Server) newPage(request pageRequest) (page, error) {
+	p := page{size: request.PageSize}
+	if p.size == 0 {
+		p.size = defaultPageSize
+	}
+	if maxSize := server.config.MaxPageSize; maxSize > 0 && p.size > maxSize {
+		return p, fmt.Errorf("page_size must be at most %d", maxSize)
+	}
+
+	if request.PageID > 0 {
+		if len(request.Cursor) > 0 {
+			return p, errors.New("page_id and cursor can not be used together")
+		}
+		p.legacy = true
+		p.offset = (request.PageID - 1) * p.size
+		return p, nil
+	}
+
+	if len(request.Cursor) > 0 {
+		cursor, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return p, err
+		}
+		p.after = cursor
+	}
+	return p, nil
+}
+
+// limit is the number of records to query, one more record is queried to know if there is a next page
+func (p page) limit() int32 {
+	return p.size + 1
+}
+
+// next returns the number of records in this page, and the cursor of the next page
+// n is the number of records returned by the query, cursorOf returns the cursor of the i-th record.
+func (p page) next(n int, cursorOf func(i int) pageCursor) (int, string) {
+	if n <= int(p.size) {
+		return n, ""
+	}
+	return int(p.size), encodeCursor(cursorOf(int(p.size) - 1))
+}
+
+// response returns the response body of the page
+// old clients with page_id only get the items.
+func (p page) response(items interface{}, nextCursor string) interface{} {
+	if p.legacy {
+		return items
+	}
+	return listResponse{
+		Items:      items,
+		NextCursor: nextCursor,
+	}
+}
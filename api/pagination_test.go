@@ -0,0 +1,68 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bank-demo/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+	cursor := pageCursor{
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		ID:        util.RandomInt(1, 1000),
+	}
+
+	token := encodeCursor(cursor)
+	require.NotEmpty(t, token)
+
+	decoded, err := decodeCursor(token)
+	require.NoError(t, err)
+	require.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, cursor.ID, decoded.ID)
+
+	_, err = decodeCursor("not base64!")
+	require.Error(t, err)
+
+	_, err = decodeCursor(encodeCursor(pageCursor{})[1:])
+	require.Error(t, err)
+}
+
+func TestNewPage(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	// cursor is not provided, start from the first record
+	p, err := server.newPage(pageRequest{PageSize: 5})
+	require.NoError(t, err)
+	require.False(t, p.legacy)
+	require.Equal(t, int32(6), p.limit())
+	require.Zero(t, p.offset)
+	require.Zero(t, p.after.ID)
+
+	// page_id is converted to offset
+	p, err = server.newPage(pageRequest{PageID: 3, PageSize: 5})
+	require.NoError(t, err)
+	require.True(t, p.legacy)
+	require.Equal(t, int32(10), p.offset)
+
+	p, err = server.newPage(pageRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(defaultPageSize), p.size)
+
+	_, err = server.newPage(pageRequest{PageSize: server.config.MaxPageSize + 1})
+	require.Error(t, err)
+
+	// next cursor is the last record of the page
+	cursors := []pageCursor{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}, {ID: 6}}
+	p, err = server.newPage(pageRequest{PageSize: 5})
+	require.NoError(t, err)
+
+	n, next := p.next(len(cursors), func(i int) pageCursor { return cursors[i] })
+	require.Equal(t, 5, n)
+	require.Equal(t, encodeCursor(cursors[4]), next)
+
+	n, next = p.next(5, func(i int) pageCursor { return cursors[i] })
+	require.Equal(t, 5, n)
+	require.Empty(t, next)
+}
@@ -0,0 +1,390 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// a scheduled transfer is a transfer made by the scheduler on a recurrence, e.g. the rent of every month.
+// the recurrence is either a standard cron expression in UTC, or a fixed interval in seconds.
+// every run is recorded, a run fails if the transfer can not be made, e.g. the balance is not enough,
+// and the schedule still goes on to the next time.
+
+// createScheduledTransferRequest to store create scheduled transfer request
+// exactly one of cron_expression and interval_seconds must be set.
+// interval_seconds is at least a minute, the same as the cron expression, and at most a year.
+// start_at is the earliest time of the first run, it is now if empty.
+type createScheduledTransferRequest struct {
+	FromAccountID   int64     `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID     int64     `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount          int64     `json:"amount" binding:"required,gt=0"`
+	CronExpression  string    `json:"cron_expression" binding:"max=100"`
+	IntervalSeconds int64     `json:"interval_seconds" binding:"omitempty,min=60,max=31536000"`
+	StartAt         time.Time `json:"start_at"`
+}
+
+// scheduledTransferResponse is the scheduled transfer object return to client
+type scheduledTransferResponse struct {
+	db.ScheduledTransfers
+	FormattedAmount string `json:"formattedAmount"`
+}
+
+func newScheduledTransferResponse(scheduled db.ScheduledTransfers, code string) scheduledTransferResponse {
+	return scheduledTransferResponse{
+		ScheduledTransfers: scheduled,
+		FormattedAmount:    currency.Format(scheduled.Amount, code),
+	}
+}
+
+// scheduledTransferRunResponse is the run object return to client
+// db.ScheduledTransferRuns has sql.NullInt64 transfer ID, it is null if the run failed.
+type scheduledTransferRunResponse struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduledTransferID"`
+	ScheduledAt         time.Time `json:"scheduledAt"`
+	Status              string    `json:"status"`
+	TransferID          *int64    `json:"transferID"`
+	Error               string    `json:"error"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+func newScheduledTransferRunResponses(runs []db.ScheduledTransferRuns) []scheduledTransferRunResponse {
+	responses := make([]scheduledTransferRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = scheduledTransferRunResponse{
+			ID:                  run.ID,
+			ScheduledTransferID: run.ScheduledTransferID,
+			ScheduledAt:         run.ScheduledAt,
+			Status:              run.Status,
+			Error:               run.Error,
+			CreatedAt:           run.CreatedAt,
+		}
+		if run.TransferID.Valid {
+			responses[i].TransferID = &runs[i].TransferID.Int64
+		}
+	}
+	return responses
+}
+
+// implement createScheduledTransfer API, POST /scheduled_transfers
+// status code:
+// 400 - input parameters are invalid, or the recurrence is invalid
+// 401 - from account is not owned by the authenticated user
+// 404 - from account or to account not found in db
+// 409 - one of the accounts is frozen or closed
+// 422 - currency of the to account is different, scheduled transfers are not converted
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var request createScheduledTransferRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	now := time.Now()
+	start := request.StartAt
+	if start.IsZero() {
+		start = now
+	}
+	if start.Before(now.Add(-time.Minute)) {
+		ctx.Error(badRequest(errors.New("start_at must not be in the past")))
+		return
+	}
+	nextRunAt, err := db.FirstScheduledRun(request.CronExpression, request.IntervalSeconds, start)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	fromAccount, valid := server.ownAccount(ctx, request.FromAccountID)
+	if !valid {
+		return
+	}
+	if _, valid := server.validAccount(ctx, request.ToAccountID, fromAccount.Currency); !valid {
+		return
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:           fromAccount.Owner,
+		FromAccountID:   request.FromAccountID,
+		ToAccountID:     request.ToAccountID,
+		Amount:          request.Amount,
+		CronExpression:  request.CronExpression,
+		IntervalSeconds: request.IntervalSeconds,
+		NextRunAt:       nextRunAt,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newScheduledTransferResponse(scheduled, fromAccount.Currency))
+}
+
+type getScheduledTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// implement getScheduledTransfer API, GET /scheduled_transfers/:id
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	var request getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	scheduled, valid := server.ownScheduledTransfer(ctx, request.ID)
+	if !valid {
+		return
+	}
+	server.scheduledTransferResponse(ctx, scheduled)
+}
+
+type listScheduledTransfersRequest struct {
+	pageRequest
+}
+
+// implement listScheduledTransfers API, GET /scheduled_transfers
+// only the scheduled transfers of the authenticated user are listed, the cancelled ones are included.
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var request listScheduledTransfersRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	page, err := server.newPage(request.pageRequest)
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	schedules, err := server.store.ListScheduledTransfersPage(ctx, db.ListScheduledTransfersPageParams{
+		Owner:          authPayload.Username,
+		AfterCreatedAt: page.after.CreatedAt,
+		AfterID:        page.after.ID,
+		PageLimit:      page.limit(),
+		PageOffset:     page.offset,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	n, nextCursor := page.next(len(schedules), func(i int) pageCursor {
+		return pageCursor{CreatedAt: schedules[i].CreatedAt, ID: schedules[i].ID}
+	})
+	schedules = schedules[:n]
+
+	// the currencies of the from accounts are queried at once
+	currencies := map[int64]string{}
+	var ids []int64
+	for _, scheduled := range schedules {
+		if _, ok := currencies[scheduled.FromAccountID]; !ok {
+			currencies[scheduled.FromAccountID] = ""
+			ids = append(ids, scheduled.FromAccountID)
+		}
+	}
+	if len(ids) > 0 {
+		rows, err := server.store.ListAccountCurrencies(ctx, ids)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		for _, row := range rows {
+			currencies[row.ID] = row.Currency
+		}
+	}
+
+	responses := make([]scheduledTransferResponse, len(schedules))
+	for i, scheduled := range schedules {
+		responses[i] = newScheduledTransferResponse(scheduled, currencies[scheduled.FromAccountID])
+	}
+	ctx.JSON(http.StatusOK, page.response(responses, nextCursor))
+}
+
+// updateScheduledTransferRequest contains the fields which can be changed by the owner
+// all fields are optional, only the provided fields are updated.
+// status pauses or resumes the schedule, the runs missed while it is paused are skipped.
+type updateScheduledTransferRequest struct {
+	Amount *int64  `json:"amount" binding:"omitempty,gt=0"`
+	Status *string `json:"status" binding:"omitempty,oneof=active paused"`
+}
+
+// implement updateScheduledTransfer API, PATCH /scheduled_transfers/:id
+// status code:
+// 401 - scheduled transfer is not owned by the authenticated user
+// 404 - scheduled transfer not found in db
+// 409 - scheduled transfer is cancelled
+func (server *Server) updateScheduledTransfer(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request updateScheduledTransferRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	if _, valid := server.ownScheduledTransfer(ctx, uri.ID); !valid {
+		return
+	}
+
+	scheduled, err := server.changeScheduledTransfer(ctx, uri.ID, func(arg *db.UpdateScheduledTransferParams) {
+		if request.Amount != nil {
+			arg.Amount = *request.Amount
+		}
+		if request.Status != nil {
+			arg.Status = *request.Status
+		}
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	server.scheduledTransferResponse(ctx, scheduled)
+}
+
+// implement deleteScheduledTransfer API, DELETE /scheduled_transfers/:id
+// the scheduled transfer is cancelled instead of deleted, so its runs are kept.
+func (server *Server) deleteScheduledTransfer(ctx *gin.Context) {
+	var request getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	if _, valid := server.ownScheduledTransfer(ctx, request.ID); !valid {
+		return
+	}
+
+	scheduled, err := server.changeScheduledTransfer(ctx, request.ID, func(arg *db.UpdateScheduledTransferParams) {
+		arg.Status = db.ScheduledTransferStatusCancelled
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	server.scheduledTransferResponse(ctx, scheduled)
+}
+
+type listScheduledTransferRunsRequest struct {
+	pageRequest
+}
+
+// implement listScheduledTransferRuns API, GET /scheduled_transfers/:id/runs
+func (server *Server) listScheduledTransferRuns(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request listScheduledTransferRunsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	page, err := server.newPage(request.pageRequest)
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	if _, valid := server.ownScheduledTransfer(ctx, uri.ID); !valid {
+		return
+	}
+
+	runs, err := server.store.ListScheduledTransferRunsPage(ctx, db.ListScheduledTransferRunsPageParams{
+		ScheduledTransferID: uri.ID,
+		AfterCreatedAt:      page.after.CreatedAt,
+		AfterID:             page.after.ID,
+		PageLimit:           page.limit(),
+		PageOffset:          page.offset,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	n, nextCursor := page.next(len(runs), func(i int) pageCursor {
+		return pageCursor{CreatedAt: runs[i].CreatedAt, ID: runs[i].ID}
+	})
+	ctx.JSON(http.StatusOK, page.response(newScheduledTransferRunResponses(runs[:n]), nextCursor))
+}
+
+// changeScheduledTransfer locks the scheduled transfer and updates it by change
+// the lock makes sure the scheduler doesn't run it at the same time,
+// otherwise the next_run_at moved by the scheduler may be overwritten.
+func (server *Server) changeScheduledTransfer(ctx *gin.Context, id int64, change func(arg *db.UpdateScheduledTransferParams)) (db.ScheduledTransfers, error) {
+	var scheduled db.ScheduledTransfers
+	err := server.store.ExecTx(ctx, nil, func(q db.Querier) error {
+		var err error
+		scheduled, err = q.GetScheduledTransferForUpdate(ctx, id)
+		if err != nil {
+			return db.TranslateNotFound(err, db.ErrScheduledTransferNotFound)
+		}
+		if scheduled.Status == db.ScheduledTransferStatusCancelled {
+			return newAPIError(http.StatusConflict, "scheduled_transfer_cancelled",
+				fmt.Errorf("scheduled transfer [%d] is cancelled", scheduled.ID))
+		}
+
+		arg := db.UpdateScheduledTransferParams{
+			ID:        scheduled.ID,
+			Amount:    scheduled.Amount,
+			Status:    scheduled.Status,
+			NextRunAt: scheduled.NextRunAt,
+		}
+		change(&arg)
+
+		// the runs missed while it is paused are skipped
+		now := time.Now()
+		if arg.Status == db.ScheduledTransferStatusActive && scheduled.Status == db.ScheduledTransferStatusPaused &&
+			!scheduled.NextRunAt.After(now) {
+			arg.NextRunAt, err = db.NextScheduledRun(scheduled, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		scheduled, err = q.UpdateScheduledTransfer(ctx, arg)
+		return err
+	})
+	return scheduled, err
+}
+
+// scheduledTransferResponse writes the scheduled transfer in the currency of its from account
+func (server *Server) scheduledTransferResponse(ctx *gin.Context, scheduled db.ScheduledTransfers) {
+	account, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newScheduledTransferResponse(scheduled, account.Currency))
+}
+
+// ownScheduledTransfer gets the scheduled transfer and checks it is owned by the authenticated user
+// if not, the error is already added to ctx, so the caller just need to return.
+func (server *Server) ownScheduledTransfer(ctx *gin.Context, id int64) (db.ScheduledTransfers, bool) {
+	scheduled, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		ctx.Error(err)
+		return scheduled, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if scheduled.Owner != authPayload.Username {
+		err := errors.New("scheduled transfer doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
+		return scheduled, false
+	}
+	return scheduled, true
+}
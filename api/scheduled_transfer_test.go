@@ -0,0 +1,622 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/token"
+	"github.com/bank-demo/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduledTransferAPI(t *testing.T) {
+	amount := int64(10)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = "USD"
+	account2.Currency = "USD"
+	scheduled := randomScheduledTransfer(account1, account2)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"cron_expression": "0 9 1 * *",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ context.Context, arg db.CreateScheduledTransferParams) (db.ScheduledTransfers, error) {
+						require.Equal(t, user1.Username, arg.Owner)
+						require.Equal(t, account1.ID, arg.FromAccountID)
+						require.Equal(t, account2.ID, arg.ToAccountID)
+						require.Equal(t, amount, arg.Amount)
+						require.Equal(t, "0 9 1 * *", arg.CronExpression)
+						require.Zero(t, arg.IntervalSeconds)
+						// 09:00 on the 1st of a month, in UTC
+						require.Equal(t, 1, arg.NextRunAt.Day())
+						require.Equal(t, 9, arg.NextRunAt.Hour())
+						require.Equal(t, time.UTC, arg.NextRunAt.Location())
+						return scheduled, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchScheduledTransfer(t, recorder.Body, scheduled)
+			},
+		},
+		{
+			name: "Interval",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 3600,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ context.Context, arg db.CreateScheduledTransferParams) (db.ScheduledTransfers, error) {
+						require.Empty(t, arg.CronExpression)
+						require.Equal(t, int64(3600), arg.IntervalSeconds)
+						// the first run is now
+						require.WithinDuration(t, time.Now(), arg.NextRunAt, 2*time.Second)
+						return scheduled, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCronExpression",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"cron_expression": "every day",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorCode(t, recorder, "invalid_schedule")
+			},
+		},
+		{
+			name: "BothRecurrences",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"cron_expression":  "0 9 1 * *",
+				"interval_seconds": 3600,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorCode(t, recorder, "invalid_schedule")
+			},
+		},
+		{
+			name: "IntervalTooShort",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 59,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorCode(t, recorder, "invalid_request")
+			},
+		},
+		{
+			name: "IntervalTooLong",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": int64(1e10),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorCode(t, recorder, "invalid_request")
+			},
+		},
+		{
+			// February 30th never comes, the schedule would be due forever
+			name: "CronNeverRuns",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"cron_expression": "0 0 30 2 *",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorCode(t, recorder, "invalid_schedule")
+			},
+		},
+		{
+			name: "StartInThePast",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 3600,
+				"start_at":         time.Now().Add(-time.Hour),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 3600,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 3600,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				twdAccount := account2
+				twdAccount.Currency = "TWD"
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(twdAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireErrorCode(t, recorder, "currency_mismatch")
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"from_account_id":  account1.ID,
+				"to_account_id":    account2.ID,
+				"amount":           amount,
+				"interval_seconds": 3600,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/scheduled_transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetScheduledTransferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	scheduled := randomScheduledTransfer(account1, account2)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user1.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchScheduledTransfer(t, recorder.Body, scheduled)
+			},
+		},
+		{
+			// the owner of the to account can not see the schedule
+			name:     "UnauthorizedUser",
+			username: user2.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user1.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).
+					Return(db.ScheduledTransfers{}, db.ErrScheduledTransferNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireErrorCode(t, recorder, "scheduled_transfer_not_found")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/scheduled_transfers/%d", scheduled.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateScheduledTransferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+
+	testCases := []struct {
+		name          string
+		method        string
+		body          gin.H
+		status        string
+		nextRunAt     time.Time
+		checkUpdate   func(t *testing.T, scheduled db.ScheduledTransfers, arg db.UpdateScheduledTransferParams)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "ChangeAmount",
+			method:    http.MethodPatch,
+			body:      gin.H{"amount": 99},
+			status:    db.ScheduledTransferStatusActive,
+			nextRunAt: time.Now().Add(time.Hour),
+			checkUpdate: func(t *testing.T, scheduled db.ScheduledTransfers, arg db.UpdateScheduledTransferParams) {
+				require.Equal(t, int64(99), arg.Amount)
+				require.Equal(t, scheduled.Status, arg.Status)
+				require.True(t, scheduled.NextRunAt.Equal(arg.NextRunAt))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Pause",
+			method:    http.MethodPatch,
+			body:      gin.H{"status": db.ScheduledTransferStatusPaused},
+			status:    db.ScheduledTransferStatusActive,
+			nextRunAt: time.Now().Add(time.Hour),
+			checkUpdate: func(t *testing.T, scheduled db.ScheduledTransfers, arg db.UpdateScheduledTransferParams) {
+				require.Equal(t, scheduled.Amount, arg.Amount)
+				require.Equal(t, db.ScheduledTransferStatusPaused, arg.Status)
+				require.True(t, scheduled.NextRunAt.Equal(arg.NextRunAt))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			// the runs missed while it is paused are skipped
+			name:      "ResumeSkipsMissedRuns",
+			method:    http.MethodPatch,
+			body:      gin.H{"status": db.ScheduledTransferStatusActive},
+			status:    db.ScheduledTransferStatusPaused,
+			nextRunAt: time.Now().Add(-150 * time.Minute),
+			checkUpdate: func(t *testing.T, scheduled db.ScheduledTransfers, arg db.UpdateScheduledTransferParams) {
+				require.Equal(t, db.ScheduledTransferStatusActive, arg.Status)
+				require.True(t, scheduled.NextRunAt.Add(3*time.Hour).Equal(arg.NextRunAt))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Cancel",
+			method:    http.MethodDelete,
+			status:    db.ScheduledTransferStatusPaused,
+			nextRunAt: time.Now().Add(time.Hour),
+			checkUpdate: func(t *testing.T, scheduled db.ScheduledTransfers, arg db.UpdateScheduledTransferParams) {
+				require.Equal(t, db.ScheduledTransferStatusCancelled, arg.Status)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "AlreadyCancelled",
+			method:    http.MethodPatch,
+			body:      gin.H{"amount": 99},
+			status:    db.ScheduledTransferStatusCancelled,
+			nextRunAt: time.Now().Add(time.Hour),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorCode(t, recorder, "scheduled_transfer_cancelled")
+			},
+		},
+		{
+			name:      "InvalidStatus",
+			method:    http.MethodPatch,
+			body:      gin.H{"status": db.ScheduledTransferStatusCancelled},
+			status:    db.ScheduledTransferStatusActive,
+			nextRunAt: time.Now().Add(time.Hour),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			scheduled := randomScheduledTransfer(account1, account2)
+			scheduled.Status = tc.status
+			scheduled.NextRunAt = tc.nextRunAt.UTC().Truncate(time.Second)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			if tc.checkUpdate != nil || tc.status == db.ScheduledTransferStatusCancelled {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				// the mock store is also a Querier, so fn runs with the mock as the transaction
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, opts *sql.TxOptions, fn func(db.Querier) error) error {
+						return fn(store)
+					})
+				store.EXPECT().GetScheduledTransferForUpdate(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+			}
+			if tc.checkUpdate != nil {
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ context.Context, arg db.UpdateScheduledTransferParams) (db.ScheduledTransfers, error) {
+						require.Equal(t, scheduled.ID, arg.ID)
+						tc.checkUpdate(t, scheduled, arg)
+						updated := scheduled
+						updated.Amount = arg.Amount
+						updated.Status = arg.Status
+						updated.NextRunAt = arg.NextRunAt
+						return updated, nil
+					})
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			} else {
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var data []byte
+			if tc.body != nil {
+				var err error
+				data, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/scheduled_transfers/%d", scheduled.ID)
+			request, err := http.NewRequest(tc.method, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListScheduledTransferRunsAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	scheduled := randomScheduledTransfer(account1, account2)
+
+	runs := []db.ScheduledTransferRuns{
+		{
+			ID:                  1,
+			ScheduledTransferID: scheduled.ID,
+			ScheduledAt:         scheduled.NextRunAt,
+			Status:              db.ScheduledTransferRunSucceeded,
+			TransferID:          sql.NullInt64{Int64: 7, Valid: true},
+		},
+		{
+			ID:                  2,
+			ScheduledTransferID: scheduled.ID,
+			ScheduledAt:         scheduled.NextRunAt.Add(time.Hour),
+			Status:              db.ScheduledTransferRunFailed,
+			Error:               "insufficient funds",
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+	store.EXPECT().ListScheduledTransferRunsPage(gomock.Any(), gomock.Eq(db.ListScheduledTransferRunsPageParams{
+		ScheduledTransferID: scheduled.ID,
+		PageLimit:           defaultPageSize + 1,
+	})).Times(1).Return(runs, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/scheduled_transfers/%d/runs", scheduled.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got struct {
+		Items      []scheduledTransferRunResponse `json:"items"`
+		NextCursor string                         `json:"next_cursor"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	require.Empty(t, got.NextCursor)
+	require.NotNil(t, got.Items[0].TransferID)
+	require.Equal(t, int64(7), *got.Items[0].TransferID)
+	require.Nil(t, got.Items[1].TransferID)
+	require.Equal(t, "insufficient funds", got.Items[1].Error)
+}
+
+func randomScheduledTransfer(account, toAccount db.Accounts) db.ScheduledTransfers {
+	return db.ScheduledTransfers{
+		ID:              util.RandomInt(1, 1000),
+		Owner:           account.Owner,
+		FromAccountID:   account.ID,
+		ToAccountID:     toAccount.ID,
+		Amount:          util.RandomInt(10, 100),
+		IntervalSeconds: 3600,
+		Status:          db.ScheduledTransferStatusActive,
+		NextRunAt:       time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+}
+
+func requireBodyMatchScheduledTransfer(t *testing.T, body *bytes.Buffer, scheduled db.ScheduledTransfers) {
+	var got scheduledTransferResponse
+	err := json.Unmarshal(body.Bytes(), &got)
+	require.NoError(t, err)
+
+	require.Equal(t, scheduled.ID, got.ID)
+	require.Equal(t, scheduled.Owner, got.Owner)
+	require.Equal(t, scheduled.FromAccountID, got.FromAccountID)
+	require.Equal(t, scheduled.ToAccountID, got.ToAccountID)
+	require.Equal(t, scheduled.Amount, got.Amount)
+	require.Equal(t, scheduled.Status, got.Status)
+	require.True(t, scheduled.NextRunAt.Equal(got.NextRunAt))
+	require.NotEmpty(t, got.FormattedAmount)
+}
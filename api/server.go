@@ -1,35 +1,112 @@
 package api
 
 import (
+	"fmt"
 
 	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/exchange"
+	"github.com/bank-demo/token"
+	"github.com/bank-demo/util"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // Server servers HTTP request for bank service
 type Server struct {
-	router *gin.Engine
-	store  db.Store
+	config     util.Config
+	router     *gin.Engine
+	store      db.Store
+	tokenMaker token.Maker
+	rates      exchange.Provider
 }
 
 // NewServer return a new HTTP server and setup router
 // -------after mock DB------
 // *db.Store change to db.Store, because interface
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
+// -------after token------
+// config is needed to create token maker, and the maker may fail with invalid key,
+// so NewServer returns an error too.
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	// PASETO is used by default, can be switched to token.NewJWTMaker()
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	// exchange rates of cross-currency transfers
+	rates := exchange.NewDBProvider(store)
+	if config.ExchangeRatesFile != "" {
+		rates, err = exchange.NewFileProvider(config.ExchangeRatesFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create exchange rate provider: %w", err)
+		}
+	}
+
+	server := &Server{
+		config:     config,
+		store:      store,
+		tokenMaker: tokenMaker,
+		rates:      rates,
+	}
+
+	// register the custom validators used by binding tags
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("currency", validCurrency)
+	}
+
+	server.setupRouter()
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
 	router := gin.Default()
+	// errorHandler writes the error response of all routes below
+	router.Use(errorHandler())
 	// add routes to router, first API is POST method
 	// server.creatAccount is a hendler
-	router.POST("/accounts", server.createAccount)
-	router.GET("/accounts/:id", server.getAccount)
-	router.GET("/accounts/", server.listAccount)
+	router.POST("/users", server.createUser)
+	router.POST("/users/login", server.loginUser)
+	router.POST("/tokens/renew_access", server.renewAccessToken)
 
-	server.router = router
-	return server
+	// routes in this group must pass the auth middleware first
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
 
-}
+	authRoutes.GET("/users/:username", server.getUser)
 
+	authRoutes.POST("/accounts", server.createAccount)
+	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.GET("/accounts/", server.listAccount)
+	authRoutes.PATCH("/accounts/:id", server.updateAccount)
+	authRoutes.POST("/accounts/:id/freeze", server.freezeAccount)
+	authRoutes.POST("/accounts/:id/unfreeze", server.unfreezeAccount)
+	authRoutes.POST("/accounts/:id/close", server.closeAccount)
+	authRoutes.GET("/accounts/:id/entries", server.listEntries)
+	authRoutes.GET("/accounts/:id/transfers", server.listTransfers)
+	authRoutes.GET("/accounts/:id/statement", server.getStatement)
+	authRoutes.GET("/accounts/:id/balance", server.getBalance)
+	authRoutes.POST("/accounts/:id/deposits", server.createDeposit)
+	authRoutes.POST("/accounts/:id/withdrawals", server.createWithdrawal)
+	authRoutes.POST("/accounts/:id/holds", server.placeHold)
 
+	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/transfers/:id", server.getTransfer)
+
+	authRoutes.GET("/holds/:id", server.getHold)
+	authRoutes.POST("/holds/:id/capture", server.captureHold)
+	authRoutes.POST("/holds/:id/release", server.releaseHold)
+
+	authRoutes.POST("/scheduled_transfers", server.createScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers", server.listScheduledTransfers)
+	authRoutes.GET("/scheduled_transfers/:id", server.getScheduledTransfer)
+	authRoutes.PATCH("/scheduled_transfers/:id", server.updateScheduledTransfer)
+	authRoutes.DELETE("/scheduled_transfers/:id", server.deleteScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers/:id/runs", server.listScheduledTransferRuns)
+
+	authRoutes.POST("/sessions/:id/block", server.blockSession)
+
+	server.router = router
+}
 
 // because router is private in api package, need to an public method for call from outside
 // Start method is run HTTP Server on the input address and listen for API request
@@ -37,9 +114,5 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 
 }
-// errorResponse will return map[string]interface{} to client
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
-}
 
 // then go to main.go to start sever
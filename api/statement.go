@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/statement"
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementDays is the longest date range of a statement
+const maxStatementDays = 366
+
+// getStatementRequest contains the date range and the file format of the statement
+// from / to: e.g. 2021-10-01, both days are included
+// format: csv, json or ofx, json is used if it is empty
+type getStatementRequest struct {
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv json ofx"`
+}
+
+// implement getStatement API, GET /accounts/:id/statement
+// the statement is returned as a downloadable file.
+// status code:
+// 400 - input parameters are invalid, or the date range is too long
+// 401 - account is not owned by the authenticated user
+// 404 - account not found in db
+func (server *Server) getStatement(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request getStatementRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	if request.To.Before(request.From) {
+		ctx.Error(badRequest(errors.New("from must not be after to")))
+		return
+	}
+	if request.To.Sub(request.From) >= maxStatementDays*24*time.Hour {
+		ctx.Error(badRequest(fmt.Errorf("date range must not be longer than %d days", maxStatementDays)))
+		return
+	}
+	format := request.Format
+	if format == "" {
+		format = statement.FormatJSON
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	result, err := statement.Build(ctx, server.store, account, request.From, request.To)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// the file is written to the buffer first, so an error can still be returned as the error response
+	var file bytes.Buffer
+	if err := result.Write(&file, format); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s-%s.%s",
+		account.ID, request.From.Format("20060102"), request.To.Format("20060102"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, statement.ContentType(format), file.Bytes())
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type renewAccessTokenResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+// implement renewAccessToken API
+// the refresh token must be valid, and match the session stored in db.
+// status code:
+// 401 - refresh token is invalid or not a refresh token, or the session is blocked / expired / mismatched
+// 404 - session of the refresh token not found in db
+func (server *Server) renewAccessToken(ctx *gin.Context) {
+	var request renewAccessTokenRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(request.RefreshToken)
+	if err != nil {
+		ctx.Error(unauthorized(err))
+		return
+	}
+	// an access token has no session, it can not be used to create another access token
+	if refreshPayload.Type != token.TokenTypeRefresh {
+		err := errors.New("token is not a refresh token")
+		ctx.Error(unauthorized(err))
+		return
+	}
+	// the ID of refresh token payload is the ID of session
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if session.IsBlocked {
+		err := errors.New("blocked session")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	if session.Username != refreshPayload.Username {
+		err := errors.New("incorrect session user")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	if session.RefreshToken != request.RefreshToken {
+		err := errors.New("mismatched session token")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		err := errors.New("expired session")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(refreshPayload.Username, token.TokenTypeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := renewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+type blockSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// implement blockSession API
+// after the session is blocked, its refresh token can not be used to renew access token anymore.
+// a user can only block the session owned by the user
+func (server *Server) blockSession(ctx *gin.Context) {
+	var request blockSessionRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	// the uuid tag already checked the format, so parsing should not fail here
+	sessionID, err := uuid.Parse(request.ID)
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, sessionID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if session.Username != authPayload.Username {
+		err := fmt.Errorf("session [%s] doesn't belong to the authenticated user", session.ID)
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	session, err = server.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"session_id": session.ID,
+		"is_blocked": session.IsBlocked,
+	})
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/exchange"
+	"github.com/bank-demo/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// transferRequest to store create transfer request
+// the money is moved from FromAccountID to ToAccountID,
+// both accounts must have the same currency as the request.
+// ToAccountID can not be the same as FromAccountID, checked by nefield tag.
+// for cross-currency transfer, ToCurrency is the currency of the to account,
+// Amount in Currency is converted by the exchange rate, the client must set it explicitly,
+// so money is never converted by mistake.
+type transferRequest struct {
+	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	ToCurrency    string `json:"to_currency,omitempty" binding:"omitempty,currency"`
+}
+
+// transferResponse is the transfer object return to client
+// amount is in the currency of the from account, toAmount is in the currency of the to account.
+type transferResponse struct {
+	db.Transfers
+	FromCurrency      string `json:"fromCurrency"`
+	ToCurrency        string `json:"toCurrency"`
+	FormattedAmount   string `json:"formattedAmount"`
+	FormattedToAmount string `json:"formattedToAmount"`
+}
+
+func newTransferResponse(transfer db.Transfers, fromCurrency string, toCurrency string) transferResponse {
+	return transferResponse{
+		Transfers:         transfer,
+		FromCurrency:      fromCurrency,
+		ToCurrency:        toCurrency,
+		FormattedAmount:   currency.Format(transfer.Amount, fromCurrency),
+		FormattedToAmount: currency.Format(transfer.ToAmount, toCurrency),
+	}
+}
+
+// transferTxResponse is db.TransferTxResult with formatted amounts
+type transferTxResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+	Fee         *feeResponse     `json:"fee,omitempty"`
+}
+
+// feeResponse is db.TransferFee with formatted amounts, the fee is in the currency of the from account
+type feeResponse struct {
+	Amount          int64            `json:"amount"`
+	FormattedAmount string           `json:"formattedAmount"`
+	Transfer        transferResponse `json:"transfer"`
+	FromEntry       entryResponse    `json:"from_entry"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) transferTxResponse {
+	fromCurrency, toCurrency := result.FromAccount.Currency, result.ToAccount.Currency
+	response := transferTxResponse{
+		Transfer:    newTransferResponse(result.Transfer, fromCurrency, toCurrency),
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount:   newAccountResponse(result.ToAccount),
+		FromEntry:   newEntryResponses([]db.Entries{result.FromEntry}, fromCurrency)[0],
+		ToEntry:     newEntryResponses([]db.Entries{result.ToEntry}, toCurrency)[0],
+	}
+	if result.Fee != nil {
+		response.Fee = &feeResponse{
+			Amount:          result.Fee.Amount,
+			FormattedAmount: currency.Format(result.Fee.Amount, fromCurrency),
+			Transfer:        newTransferResponse(result.Fee.Transfer, fromCurrency, fromCurrency),
+			FromEntry:       newEntryResponses([]db.Entries{result.Fee.FromEntry}, fromCurrency)[0],
+		}
+	}
+	return response
+}
+
+// implement createTransfer API
+// the errors are written by errorHandler, status code:
+// 400 - input parameters are invalid
+// 401 - from account is not owned by the authenticated user
+// 404 - from or to account not found in db
+// 422 - currency of the accounts doesn't match the request, or the exchange rate is not found
+// 409 - balance of the from account is not enough, or one of the accounts is frozen or closed
+// 422 - Idempotency-Key is reused with a different request
+// 422 - the transfer exceeds the transfer limits of the from account
+// 500 - error between server and db
+func (server *Server) createTransfer(ctx *gin.Context) {
+	var request transferRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	// if the request is a retry with the same Idempotency-Key, return the saved response directly.
+	// it must be checked before validating the accounts, because the balance has been changed by the first request
+	idem, ok := newIdempotentRequest(ctx, authPayload.Username, request)
+	if !ok {
+		return
+	}
+	if idem != nil && server.replay(ctx, idem) {
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, request.FromAccountID, request.Currency)
+	if !valid {
+		return
+	}
+	// a user can only send money out from the account owned by the user
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
+		return
+	}
+	// money is moving out of the from account, so check its balance before transfer
+	// the money reserved by holds can not be used, the overdraft limit can be
+	if spendable := db.SpendableBalance(fromAccount); spendable < request.Amount {
+		err := fmt.Errorf("%w: account [%d] spendable balance %d < %d", db.ErrInsufficientFunds, fromAccount.ID, spendable, request.Amount)
+		ctx.Error(err)
+		return
+	}
+
+	toCurrency := request.Currency
+	if request.ToCurrency != "" {
+		toCurrency = request.ToCurrency
+	}
+	if _, valid := server.validAccount(ctx, request.ToAccountID, toCurrency); !valid {
+		return
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: request.FromAccountID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        request.Amount,
+	}
+	if toCurrency != request.Currency {
+		rate, err := server.rates.GetRate(ctx, request.Currency, toCurrency)
+		if err != nil {
+			if errors.Is(err, exchange.ErrRateNotFound) {
+				err = newAPIError(http.StatusUnprocessableEntity, "exchange_rate_not_found", err)
+			}
+			ctx.Error(err)
+			return
+		}
+		arg.ExchangeRate = rate
+	}
+	// the idempotency key is saved within the same transaction of the transfer
+	if idem != nil {
+		arg.AfterTransfer = func(q db.Querier, result db.TransferTxResult) error {
+			return idem.save(ctx, q, http.StatusOK, newTransferTxResponse(result))
+		}
+	}
+	// TransferTx create transfer record, 2 entries and update balance of 2 accounts
+	// within a single db transaction
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		// another request with the same key is committed first, this transfer is rolled back
+		if idem != nil && isDuplicateKeyError(err) && server.replay(ctx, idem) {
+			return
+		}
+		// the balance may be changed by another transfer after the check above,
+		// then the constraint in db rejects the transfer with db.ErrInsufficientFunds
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTransferTxResponse(result))
+}
+
+// validAccount checks if an account with the specific ID really exists,
+// and its currency matches the input currency.
+// if the account is not valid, the error is already added to ctx,
+// so the caller just need to return.
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Accounts, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		ctx.Error(err)
+		return account, false
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("%w: account [%d] %s vs %s", db.ErrCurrencyMismatch, account.ID, account.Currency, currency)
+		ctx.Error(err)
+		return account, false
+	}
+	// the system accounts of the bank are only used by deposits, withdrawals and interest
+	if db.IsSystemAccount(account) {
+		ctx.Error(fmt.Errorf("%w: account [%d]", db.ErrAccountNotFound, account.ID))
+		return account, false
+	}
+	// TransferTx checks the status again within the transaction,
+	// here is just to return the error early
+	if account.Status != db.AccountStatusActive {
+		err := fmt.Errorf("%w: account [%d] is %s", db.ErrAccountNotActive, account.ID, account.Status)
+		ctx.Error(err)
+		return account, false
+	}
+
+	return account, true
+}
+
+type getTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// implement getTransfer API
+// a user can get the transfer if the user owns the from account or the to account.
+// status code:
+// 401 - neither of the accounts is owned by the authenticated user
+// 404 - transfer not found in db
+func (server *Server) getTransfer(ctx *gin.Context) {
+	var request getTransferRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, request.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// both accounts are needed, their currencies are used to format the amounts
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts := make([]db.Accounts, 2)
+	owned := false
+	for i, accountID := range []int64{transfer.FromAccountID, transfer.ToAccountID} {
+		accounts[i], err = server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if accounts[i].Owner == authPayload.Username {
+			owned = true
+		}
+	}
+	if !owned {
+		err = errors.New("transfer doesn't belong to the authenticated user")
+		ctx.Error(unauthorized(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTransferResponse(transfer, accounts[0].Currency, accounts[1].Currency))
+}
+
+// implement listTransfers API, GET /accounts/:id/transfers
+// a user can only list the transfers of the account owned by the user
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	var request listHistoryRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	filter, err := request.filter()
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+	page, err := server.newPage(request.pageRequest)
+	if err != nil {
+		ctx.Error(badRequest(err))
+		return
+	}
+
+	account, valid := server.ownAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, db.ListAccountTransfersParams{
+		AccountID:      account.ID,
+		Incoming:       filter.incoming,
+		Outgoing:       filter.outgoing,
+		MinAmount:      filter.minAmount,
+		MaxAmount:      filter.maxAmount,
+		StartTime:      filter.startTime,
+		EndTime:        filter.endTime,
+		AfterCreatedAt: page.after.CreatedAt,
+		AfterID:        page.after.ID,
+		PageLimit:      page.limit(),
+		PageOffset:     page.offset,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	n, nextCursor := page.next(len(transfers), func(i int) pageCursor {
+		return pageCursor{CreatedAt: transfers[i].CreatedAt, ID: transfers[i].ID}
+	})
+	responses, err := server.newTransferResponses(ctx, account, transfers[:n])
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, page.response(responses, nextCursor))
+}
+
+// newTransferResponses formats the transfers of the account
+// the currencies of the other accounts are queried at once, instead of one query for each transfer.
+func (server *Server) newTransferResponses(ctx *gin.Context, account db.Accounts, transfers []db.Transfers) ([]transferResponse, error) {
+	currencies := map[int64]string{account.ID: account.Currency}
+	var ids []int64
+	for _, transfer := range transfers {
+		for _, id := range []int64{transfer.FromAccountID, transfer.ToAccountID} {
+			if _, ok := currencies[id]; !ok {
+				currencies[id] = ""
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	if len(ids) > 0 {
+		rows, err := server.store.ListAccountCurrencies(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			currencies[row.ID] = row.Currency
+		}
+	}
+
+	responses := make([]transferResponse, len(transfers))
+	for i, transfer := range transfers {
+		responses[i] = newTransferResponse(transfer, currencies[transfer.FromAccountID], currencies[transfer.ToAccountID])
+	}
+	return responses, nil
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/bank-demo/currency"
+	"github.com/go-playground/validator/v10"
+)
+
+// validCurrency is the custom validator of the currency tag
+// the supported currencies are defined in the currency package,
+// so a new currency doesn't need to change every binding tag.
+var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if code, ok := fieldLevel.Field().Interface().(string); ok {
+		return currency.IsSupported(code)
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ISO 4217 codes of the supported currencies
+// they must be the same as the rows in the currencies table.
+const (
+	USD = "USD"
+	TWD = "TWD"
+)
+
+// Currency describes how the amount of a currency is stored and displayed
+// balances and amounts are int64 in the minor unit of the currency, e.g. cents of USD,
+// MinorUnits is the exponent of the minor unit, 1 USD = 10^2 cents.
+type Currency struct {
+	Code       string
+	Name       string
+	MinorUnits int
+}
+
+// registry of the supported currencies, the minor units follow ISO 4217
+var currencies = map[string]Currency{
+	USD: {Code: USD, Name: "US Dollar", MinorUnits: 2},
+	TWD: {Code: TWD, Name: "New Taiwan Dollar", MinorUnits: 2},
+}
+
+// Get returns the currency of the code
+func Get(code string) (Currency, bool) {
+	c, ok := currencies[code]
+	return c, ok
+}
+
+// IsSupported returns true if the currency is supported
+func IsSupported(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// Codes returns the codes of all supported currencies in order
+func Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Format returns the amount in minor unit as a decimal string, e.g. 12345 USD -> "123.45"
+func (c Currency) Format(amount int64) string {
+	negative := amount < 0
+	// -amount overflows for math.MinInt64, so convert to uint64 first
+	abs := uint64(amount)
+	if negative {
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if c.MinorUnits > 0 {
+		if len(digits) <= c.MinorUnits {
+			digits = strings.Repeat("0", c.MinorUnits-len(digits)+1) + digits
+		}
+		point := len(digits) - c.MinorUnits
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Format returns the amount of the currency code as a decimal string
+// the amount is not scaled if the currency is unknown.
+func Format(amount int64, code string) string {
+	c, ok := Get(code)
+	if !ok {
+		return strconv.FormatInt(amount, 10)
+	}
+	return c.Format(amount)
+}
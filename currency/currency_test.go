@@ -0,0 +1,53 @@
+package currency
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	c, ok := Get(USD)
+	require.True(t, ok)
+	require.Equal(t, USD, c.Code)
+	require.Equal(t, 2, c.MinorUnits)
+
+	_, ok = Get("XYZ")
+	require.False(t, ok)
+
+	require.True(t, IsSupported(TWD))
+	require.False(t, IsSupported("usd"))
+	require.Equal(t, []string{TWD, USD}, Codes())
+}
+
+func TestFormat(t *testing.T) {
+	usd := Currency{Code: USD, MinorUnits: 2}
+	jpy := Currency{Code: "JPY", MinorUnits: 0}
+	bhd := Currency{Code: "BHD", MinorUnits: 3}
+
+	testCases := []struct {
+		currency Currency
+		amount   int64
+		result   string
+	}{
+		{usd, 12345, "123.45"},
+		{usd, 5, "0.05"},
+		{usd, 0, "0.00"},
+		{usd, -5, "-0.05"},
+		{usd, -12345, "-123.45"},
+		{usd, math.MinInt64, "-92233720368547758.08"},
+		{jpy, 12345, "12345"},
+		{jpy, -1, "-1"},
+		{bhd, 1, "0.001"},
+		{bhd, 12345, "12.345"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.result, tc.currency.Format(tc.amount))
+	}
+
+	require.Equal(t, "1.00", Format(100, USD))
+	// unknown currency is not scaled
+	require.Equal(t, "100", Format(100, "XYZ"))
+}
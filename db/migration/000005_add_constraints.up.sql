@@ -0,0 +1,19 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL
+);
+
+INSERT INTO "currencies" ("code", "name") VALUES
+  ('USD', 'US Dollar'),
+  ('TWD', 'New Taiwan Dollar');
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_currency_fkey" FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_amount_positive" CHECK ("amount" > 0);
+
+-- balance can not be lower than -overdraft_limit, default 0 means balance must be non-negative
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_overdraft_limit_non_negative" CHECK ("overdraft_limit" >= 0);
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_balance_limit" CHECK ("balance" >= -"overdraft_limit");
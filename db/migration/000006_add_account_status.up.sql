@@ -0,0 +1,11 @@
+-- active: can send and receive money
+-- frozen: can not send or receive money until it is unfrozen
+-- closed: can not be used anymore, only an account with zero balance can be closed
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_status_valid" CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_closed_zero_balance" CHECK ("status" <> 'closed' OR "balance" = 0);
+
+-- nickname is chosen by the owner, e.g. "saving"
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- lists are paginated by the cursor (created_at, id), so created_at can not be null
+UPDATE "accounts" SET "created_at" = now() WHERE "created_at" IS NULL;
+
+ALTER TABLE "accounts" ALTER COLUMN "created_at" SET NOT NULL;
+
+CREATE INDEX "accounts_owner_created_at_id_idx" ON "accounts" ("owner", "created_at", "id");
+
+CREATE INDEX "entries_account_id_created_at_id_idx" ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX "transfers_from_account_id_created_at_id_idx" ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX "transfers_to_account_id_created_at_id_idx" ON "transfers" ("to_account_id", "created_at", "id");
@@ -0,0 +1,17 @@
+-- the other side of every deposit and withdrawal is an entry of a user account,
+-- deleting only the cash side would leave the ledger unbalanced,
+-- so the migration can not be rolled back once the cash accounts are used.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "transfers" t
+    JOIN "accounts" a ON a."id" IN (t."from_account_id", t."to_account_id")
+    WHERE a."owner" = 'system'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back 000008_add_cash_accounts: the cash accounts have transfers';
+  END IF;
+END $$;
+
+DELETE FROM "accounts" WHERE "owner" = 'system';
+
+DELETE FROM "users" WHERE "username" = 'system';
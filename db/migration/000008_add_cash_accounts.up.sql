@@ -0,0 +1,9 @@
+-- the bank itself, it owns the cash account of each currency.
+-- hashed_password is empty, so nobody can login as this user
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('system', '', 'System', 'system@bank-demo.local');
+
+-- money comes from or goes to the outside of the bank through the cash account,
+-- its balance is the negative of the money deposited, so it has no overdraft limit
+INSERT INTO "accounts" ("owner", "balance", "currency", "overdraft_limit")
+SELECT 'system', 0, "code", 9223372036854775807 FROM "currencies";
@@ -0,0 +1,9 @@
+ALTER TABLE IF EXISTS "transfers" DROP CONSTRAINT IF EXISTS "transfers_to_amount_positive";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "rounding_residue";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_amount";
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,38 @@
+-- held_amount is the sum of the active holds of the account,
+-- the money is still in the balance, but it can not be sent out until the hold is captured or released.
+-- available balance = balance - held_amount, it can not be lower than -overdraft_limit
+ALTER TABLE "accounts" ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_held_amount_non_negative" CHECK ("held_amount" >= 0);
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_available_limit" CHECK ("balance" - "held_amount" >= -"overdraft_limit");
+
+-- held: the money is reserved for to_account_id
+-- captured: the money is transferred, transfer_id is the transfer
+-- released: the hold is cancelled
+-- expired: the hold is not captured before expires_at
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'held',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_status_valid" CHECK ("status" IN ('held', 'captured', 'released', 'expired'));
+
+CREATE INDEX ON "holds" ("account_id");
+
+CREATE INDEX ON "holds" ("status", "expires_at");
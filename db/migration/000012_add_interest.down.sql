@@ -0,0 +1,41 @@
+-- the migration can not be rolled back if:
+-- the interest has been paid, the other side of every payment is an entry of a user account,
+-- deleting only the interest side would leave the ledger unbalanced;
+-- or a user has a checking and a savings account of the same currency,
+-- UNIQUE (owner, currency) can not be added back, and the accounts can not be merged without new transfers.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "transfers" t
+    JOIN "accounts" a ON a."id" IN (t."from_account_id", t."to_account_id")
+    WHERE a."owner" = 'interest'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back 000012_add_interest: the interest expense accounts have transfers';
+  END IF;
+
+  IF EXISTS (
+    SELECT 1 FROM "accounts"
+    GROUP BY "owner", "currency"
+    HAVING count(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back 000012_add_interest: some users have more than one account of the same currency';
+  END IF;
+END $$;
+
+DROP TABLE IF EXISTS interest_accruals;
+
+DROP TABLE IF EXISTS interest_rates;
+
+DELETE FROM "accounts" WHERE "owner" = 'interest';
+
+DELETE FROM "users" WHERE "username" = 'interest';
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "accrued_interest";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_type_key";
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_type_valid";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "type";
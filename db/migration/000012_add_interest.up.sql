@@ -0,0 +1,56 @@
+-- checking: the default account, usually no interest
+-- savings: earns interest at the annual rate of its type
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_type_valid" CHECK ("type" IN ('checking', 'savings'));
+
+-- a user can have a checking and a savings account of the same currency
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_type_key" UNIQUE ("owner", "currency", "type");
+
+-- accrued_interest is the interest accrued every day but not paid yet, in the minor unit of the currency,
+-- it is paid into the balance once a month.
+ALTER TABLE "accounts" ADD COLUMN "accrued_interest" bigint NOT NULL DEFAULT 0;
+
+-- annual_rate is a fraction, e.g. 0.015 is 1.5% per year
+CREATE TABLE "interest_rates" (
+  "account_type" varchar PRIMARY KEY,
+  "annual_rate" numeric NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "interest_rates" ADD CONSTRAINT "interest_rates_annual_rate_non_negative" CHECK ("annual_rate" >= 0);
+
+INSERT INTO "interest_rates" ("account_type", "annual_rate") VALUES
+  ('checking', 0),
+  ('savings', 0.015);
+
+-- the interest of one account for one day, the unique key makes the daily accrual safe to run again.
+-- transfer_id is the monthly interest payment, it is null until the interest is paid.
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate" numeric NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "interest_accruals" ADD CONSTRAINT "interest_accruals_account_date_key" UNIQUE ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("account_id", "transfer_id");
+
+-- the interest is paid by the bank from the interest expense account of each currency,
+-- like the cash accounts, its balance is the negative of the interest paid.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('interest', '', 'Interest Expense', 'interest@bank-demo.local');
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "overdraft_limit")
+SELECT 'interest', 0, "code", 9223372036854775807 FROM "currencies";
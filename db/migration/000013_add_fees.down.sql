@@ -0,0 +1,19 @@
+-- the other side of every fee is an entry of a user account,
+-- deleting only the fee revenue side would leave the ledger unbalanced,
+-- so the migration can not be rolled back once the fees are charged.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "transfers" t
+    JOIN "accounts" a ON a."id" IN (t."from_account_id", t."to_account_id")
+    WHERE a."owner" = 'fees'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back 000013_add_fees: the fee revenue accounts have transfers';
+  END IF;
+END $$;
+
+DROP TABLE IF EXISTS fee_rules;
+
+DELETE FROM "accounts" WHERE "owner" = 'fees';
+
+DELETE FROM "users" WHERE "username" = 'fees';
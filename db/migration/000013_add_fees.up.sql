@@ -0,0 +1,30 @@
+-- the fee of a transfer is decided by the rule of the currency of the from account
+-- with the largest min_amount which is not more than the amount, so the rules of a currency are the tiers:
+-- fee = flat_fee + amount * percentage, then it is raised to min_fee and capped by max_fee (0 means no cap).
+-- e.g. flat fee: flat_fee = 30, percentage fee: percentage = 0.001
+-- no fee is charged if there is no rule for the currency.
+CREATE TABLE "fee_rules" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "percentage" numeric NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fee_rules" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fee_rules" ADD CONSTRAINT "fee_rules_currency_min_amount_key" UNIQUE ("currency", "min_amount");
+
+ALTER TABLE "fee_rules" ADD CONSTRAINT "fee_rules_non_negative" CHECK (
+  "min_amount" >= 0 AND "flat_fee" >= 0 AND "percentage" >= 0 AND "min_fee" >= 0 AND "max_fee" >= 0
+);
+
+-- the fees are transferred to the fee revenue account of each currency
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('fees', '', 'Fee Revenue', 'fees@bank-demo.local');
+
+INSERT INTO "accounts" ("owner", "balance", "currency")
+SELECT 'fees', 0, "code" FROM "currencies";
@@ -0,0 +1,34 @@
+-- limits of the outgoing transfers of an account, in the minor unit of the currency of the account, 0 means no limit
+-- max_amount: the amount of a single transfer
+-- daily_amount: the total amount of the transfers of a day (UTC)
+-- daily_count: the number of the transfers of a day (UTC)
+-- the limits of an account in account_transfer_limits replace the limits of its type in transfer_limits.
+CREATE TABLE "transfer_limits" (
+  "account_type" varchar PRIMARY KEY,
+  "max_amount" bigint NOT NULL DEFAULT 0,
+  "daily_amount" bigint NOT NULL DEFAULT 0,
+  "daily_count" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_non_negative" CHECK (
+  "max_amount" >= 0 AND "daily_amount" >= 0 AND "daily_count" >= 0
+);
+
+INSERT INTO "transfer_limits" ("account_type") VALUES
+  ('checking'),
+  ('savings');
+
+CREATE TABLE "account_transfer_limits" (
+  "account_id" bigint PRIMARY KEY,
+  "max_amount" bigint NOT NULL DEFAULT 0,
+  "daily_amount" bigint NOT NULL DEFAULT 0,
+  "daily_count" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_transfer_limits" ADD CONSTRAINT "account_transfer_limits_non_negative" CHECK (
+  "max_amount" >= 0 AND "daily_amount" >= 0 AND "daily_count" >= 0
+);
@@ -0,0 +1,12 @@
+-- balance is the sum of the entries of the account created before snapshot_at,
+-- so the balance at any time is the balance of the latest snapshot plus the entries after it,
+-- and only the entries since the last snapshot are summed.
+CREATE TABLE "balance_snapshots" (
+  "account_id" bigint NOT NULL,
+  "snapshot_at" timestamptz NOT NULL,
+  "balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "snapshot_at")
+);
+
+ALTER TABLE "balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
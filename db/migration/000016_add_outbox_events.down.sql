@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS accounts_outbox_update ON "accounts";
+
+DROP TRIGGER IF EXISTS accounts_outbox_insert ON "accounts";
+
+DROP TRIGGER IF EXISTS transfers_outbox_insert ON "transfers";
+
+DROP FUNCTION IF EXISTS outbox_account_changed();
+
+DROP FUNCTION IF EXISTS outbox_transfer_created();
+
+DROP TABLE IF EXISTS outbox_events;
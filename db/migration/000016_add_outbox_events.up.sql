@@ -0,0 +1,72 @@
+-- transactional outbox: the events are written by triggers in the same transaction as the change,
+-- so an event is saved if and only if the change is committed.
+-- the relay publishes the unpublished events in order of id and sets published_at,
+-- an event may be published more than once if the relay fails before published_at is saved (at-least-once).
+-- payload is the row of the changed record, with the column names as the keys.
+-- locked_until is the lease of the relay which claimed the event, another relay skips the event until then,
+-- so the relay doesn't hold a transaction open while the events are sent over the network.
+-- failed_at is set when the event failed too many times, such dead events are not published anymore,
+-- so they don't block the events after them. they are kept in the table to be checked by hand.
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" bigint NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "locked_until" timestamptz,
+  "failed_at" timestamptz
+);
+
+CREATE INDEX ON "outbox_events" ("id") WHERE "published_at" IS NULL AND "failed_at" IS NULL;
+
+CREATE INDEX ON "outbox_events" ("aggregate_type", "aggregate_id");
+
+-- every transfer, including deposits, withdrawals, fees and interest payments
+CREATE FUNCTION outbox_transfer_created() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
+  VALUES ('transfer', NEW.id, 'transfer.created', to_jsonb(NEW));
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transfers_outbox_insert AFTER INSERT ON "transfers"
+FOR EACH ROW EXECUTE PROCEDURE outbox_transfer_created();
+
+-- the changes of balance, held_amount and accrued_interest are not published as account events,
+-- the money only moves by transfers, which have their own events.
+CREATE FUNCTION outbox_account_changed() RETURNS trigger AS $$
+DECLARE
+  event_type varchar;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    event_type := 'account.created';
+  ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+    event_type := 'account.status_changed';
+  ELSE
+    event_type := 'account.updated';
+  END IF;
+
+  INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
+  VALUES ('account', NEW.id, event_type, to_jsonb(NEW));
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_outbox_insert AFTER INSERT ON "accounts"
+FOR EACH ROW EXECUTE PROCEDURE outbox_account_changed();
+
+CREATE TRIGGER accounts_outbox_update AFTER UPDATE ON "accounts"
+FOR EACH ROW WHEN (
+  OLD.status IS DISTINCT FROM NEW.status OR
+  OLD.nickname IS DISTINCT FROM NEW.nickname OR
+  OLD.overdraft_limit IS DISTINCT FROM NEW.overdraft_limit OR
+  OLD.owner IS DISTINCT FROM NEW.owner OR
+  OLD.currency IS DISTINCT FROM NEW.currency OR
+  OLD.type IS DISTINCT FROM NEW.type
+)
+EXECUTE PROCEDURE outbox_account_changed();
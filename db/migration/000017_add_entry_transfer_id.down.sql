@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS entries DROP COLUMN IF EXISTS transfer_id;
@@ -0,0 +1,44 @@
+-- every entry is created by a transfer, the entry refers to it,
+-- so the reconciliation matches the entries by transfer_id instead of the amount and the time.
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+-- the existing entries are matched with the old rule: same account, amount and created_at.
+-- the transfers and entries created in one transaction share now(), and both are inserted in order,
+-- so the n-th entry of a group is paired with the n-th transfer of the same group.
+UPDATE entries e SET transfer_id = m.transfer_id
+FROM (
+  SELECT e.id AS entry_id, t.id AS transfer_id
+  FROM (
+    SELECT id, account_id, amount, created_at,
+      row_number() OVER (PARTITION BY account_id, amount, created_at ORDER BY id) AS n
+    FROM entries
+    WHERE amount < 0
+  ) e
+  JOIN (
+    SELECT id, from_account_id, amount, created_at,
+      row_number() OVER (PARTITION BY from_account_id, amount, created_at ORDER BY id) AS n
+    FROM transfers
+  ) t ON t.from_account_id = e.account_id AND -t.amount = e.amount AND t.created_at = e.created_at AND t.n = e.n
+) m
+WHERE e.id = m.entry_id;
+
+UPDATE entries e SET transfer_id = m.transfer_id
+FROM (
+  SELECT e.id AS entry_id, t.id AS transfer_id
+  FROM (
+    SELECT id, account_id, amount, created_at,
+      row_number() OVER (PARTITION BY account_id, amount, created_at ORDER BY id) AS n
+    FROM entries
+    WHERE amount > 0
+  ) e
+  JOIN (
+    SELECT id, to_account_id, to_amount, created_at,
+      row_number() OVER (PARTITION BY to_account_id, to_amount, created_at ORDER BY id) AS n
+    FROM transfers
+  ) t ON t.to_account_id = e.account_id AND t.to_amount = e.amount AND t.created_at = e.created_at AND t.n = e.n
+) m
+WHERE e.id = m.entry_id;
@@ -3,9 +3,10 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
 RETURNING *;
 
@@ -19,11 +20,42 @@ WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE;
 
 
+-- name: GetCashAccount :one
+-- the cash account of the currency, owned by the system user
+SELECT * FROM accounts
+WHERE owner = 'system' AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+-- name: GetInterestExpenseAccount :one
+-- the interest expense account of the currency, owned by the interest user
+SELECT * FROM accounts
+WHERE owner = 'interest' AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+-- name: GetFeeRevenueAccount :one
+-- the fee revenue account of the currency, owned by the fees user
+SELECT * FROM accounts
+WHERE owner = 'fees' AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+
 -- name: ListAccounts :many
 SELECT * FROM accounts
+WHERE owner = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2;
+LIMIT $2
+OFFSET $3;
+
+-- name: ListAccountsPage :many
+-- keyset pagination, only the accounts after the cursor (created_at, id) are listed.
+-- page_offset is only used by the old clients with page_id, it is 0 for the cursor.
+SELECT * FROM accounts
+WHERE
+    owner = sqlc.arg(owner) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
 
 -- name: UpdateAccount :one
 UPDATE accounts
@@ -37,8 +69,43 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: SetAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountNickname :one
+UPDATE accounts
+SET nickname = sqlc.arg(nickname)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts 
 WHERE id = $1;
 
 
+
+-- name: ListAccountCurrencies :many
+-- currencies of the accounts, e.g. the counterparties in a page of transfers
+SELECT id, currency FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountAccruedInterest :one
+UPDATE accounts
+SET accrued_interest = accrued_interest + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
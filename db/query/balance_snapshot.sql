@@ -0,0 +1,25 @@
+-- name: CreateBalanceSnapshot :one
+INSERT INTO balance_snapshots (
+    account_id,
+    snapshot_at,
+    balance
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: ListBalanceSnapshots :many
+SELECT * FROM balance_snapshots
+WHERE account_id = $1
+ORDER BY snapshot_at DESC
+LIMIT $2;
+
+-- name: ListAccountsToSnapshot :many
+-- the accounts created before the time, which don't have a snapshot at the time yet
+SELECT a.id FROM accounts a
+WHERE
+    a.created_at < sqlc.arg(snapshot_at)::timestamptz AND
+    NOT EXISTS (
+        SELECT 1 FROM balance_snapshots s
+        WHERE s.account_id = a.id AND s.snapshot_at = sqlc.arg(snapshot_at)::timestamptz
+    )
+ORDER BY a.id;
@@ -1,9 +1,10 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id
 ) VALUES(
-    $1, $2
+    $1, $2, $3
 )RETURNING *;
 
 -- name: GetEntry :one
@@ -15,4 +16,50 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListAccountEntries :many
+-- incoming entries have positive amount, outgoing entries have negative amount,
+-- the amount range is compared with the absolute value of amount.
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    (
+        (sqlc.arg(incoming)::boolean AND amount > 0) OR
+        (sqlc.arg(outgoing)::boolean AND amount < 0)
+    ) AND
+    abs(amount) BETWEEN sqlc.arg(min_amount)::bigint AND sqlc.arg(max_amount)::bigint AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: GetAccountBalanceBefore :one
+-- the balance of the account is always the sum of its entries, see reconcile.sql
+-- it is the balance of the latest snapshot before the time, plus the entries since the snapshot.
+WITH snapshot AS (
+    SELECT snapshot_at, balance FROM balance_snapshots
+    WHERE account_id = sqlc.arg(account_id) AND snapshot_at <= sqlc.arg(before)::timestamptz
+    ORDER BY snapshot_at DESC
+    LIMIT 1
+)
+SELECT (
+    COALESCE((SELECT balance FROM snapshot), 0) +
+    COALESCE((
+        SELECT SUM(e.amount) FROM entries e
+        WHERE
+            e.account_id = sqlc.arg(account_id) AND
+            e.created_at >= COALESCE((SELECT snapshot_at FROM snapshot), '-infinity'::timestamptz) AND
+            e.created_at < sqlc.arg(before)::timestamptz
+    ), 0)
+)::bigint AS balance;
+
+-- name: ListEntriesBetween :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    created_at >= sqlc.arg(start_time)::timestamptz AND
+    created_at < sqlc.arg(end_time)::timestamptz
+ORDER BY created_at, id;
@@ -0,0 +1,26 @@
+-- name: CreateFeeRule :one
+INSERT INTO fee_rules (
+    currency,
+    min_amount,
+    flat_fee,
+    percentage,
+    min_fee,
+    max_fee
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetFeeRule :one
+-- the tier of the amount
+SELECT * FROM fee_rules
+WHERE currency = sqlc.arg(currency) AND min_amount <= sqlc.arg(amount)
+ORDER BY min_amount DESC
+LIMIT 1;
+
+-- name: ListFeeRules :many
+SELECT * FROM fee_rules
+ORDER BY currency, min_amount;
+
+-- name: DeleteFeeRule :exec
+DELETE FROM fee_rules
+WHERE id = $1;
@@ -0,0 +1,38 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: SetHoldStatus :one
+UPDATE holds
+SET status = sqlc.arg(status), updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CaptureHold :one
+UPDATE holds
+SET status = 'captured', transfer_id = sqlc.arg(transfer_id)::bigint, updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ExpireHolds :many
+-- the expired holds are locked until the end of the transaction,
+-- then the held_amount of their accounts is released.
+UPDATE holds
+SET status = 'expired', updated_at = now()
+WHERE status = 'held' AND expires_at <= now()
+RETURNING *;
@@ -0,0 +1,59 @@
+-- name: GetInterestRate :one
+SELECT * FROM interest_rates
+WHERE account_type = $1 LIMIT 1;
+
+-- name: ListInterestRates :many
+SELECT * FROM interest_rates
+ORDER BY account_type;
+
+-- name: UpsertInterestRate :one
+INSERT INTO interest_rates (
+    account_type,
+    annual_rate
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (account_type) DO UPDATE
+SET annual_rate = EXCLUDED.annual_rate, updated_at = now()
+RETURNING *;
+
+-- name: ListAccountsToAccrueInterest :many
+-- the active accounts with positive balance and rate, which are not accrued on the date yet
+SELECT a.id FROM accounts a
+JOIN interest_rates r ON r.account_type = a.type
+WHERE
+    a.status = 'active' AND
+    a.balance > 0 AND
+    r.annual_rate > 0 AND
+    NOT EXISTS (
+        SELECT 1 FROM interest_accruals i
+        WHERE i.account_id = a.id AND i.accrual_date = sqlc.arg(accrual_date)::date
+    )
+ORDER BY a.id;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    annual_rate,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListAccountsToPayInterest :many
+SELECT id FROM accounts
+WHERE accrued_interest > 0
+ORDER BY id;
+
+-- name: PayInterestAccruals :execrows
+-- the accruals paid by the transfer
+UPDATE interest_accruals
+SET transfer_id = sqlc.arg(transfer_id)::bigint
+WHERE account_id = sqlc.arg(account_id) AND transfer_id IS NULL;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date;
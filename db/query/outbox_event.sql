@@ -0,0 +1,53 @@
+-- name: CreateOutboxEvent :one
+-- most events are written by the triggers in the migration 000016_add_outbox_events
+INSERT INTO outbox_events (
+    aggregate_type,
+    aggregate_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ClaimOutboxEvents :many
+-- the unpublished events are leased to the relay until locked_until, another relay skips them.
+-- the rows are only locked by the statement itself, the events are published after it is committed.
+-- the order of RETURNING is not defined, the caller sorts the events by id.
+UPDATE outbox_events
+SET locked_until = sqlc.arg(locked_until)::timestamptz
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE published_at IS NULL AND failed_at IS NULL AND (locked_until IS NULL OR locked_until < now())
+    ORDER BY id
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseOutboxEvents :exec
+-- the events can be claimed again immediately, instead of waiting for the lease to expire
+UPDATE outbox_events
+SET locked_until = NULL
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND published_at IS NULL;
+
+-- name: MarkOutboxEventPublished :one
+UPDATE outbox_events
+SET published_at = now(), attempts = attempts + 1, last_error = '', locked_until = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOutboxEventFailed :one
+-- the event is dead when it has failed max_attempts times, failed_at is set and it is not claimed anymore
+UPDATE outbox_events
+SET
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    failed_at = CASE WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListOutboxEventsByAggregate :many
+SELECT * FROM outbox_events
+WHERE aggregate_type = $1 AND aggregate_id = $2
+ORDER BY id;
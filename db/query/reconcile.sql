@@ -0,0 +1,63 @@
+-- name: CountAccounts :one
+SELECT count(*) FROM accounts;
+
+-- name: CountTransfers :one
+SELECT count(*) FROM transfers;
+
+-- name: ListBalanceMismatches :many
+-- the balance of an account should always be the sum of its entries
+SELECT
+    a.id AS account_id,
+    a.balance,
+    COALESCE(sum(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(sum(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListUnmatchedTransfers :many
+-- the entries refer to the transfer which created them,
+-- a transfer should have exactly one entry of each account with the amount of the transfer.
+SELECT
+    t.id AS transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    count(DISTINCT f.id) AS from_entries,
+    count(DISTINCT d.id) AS to_entries
+FROM transfers t
+LEFT JOIN entries f ON
+    f.transfer_id = t.id AND
+    f.account_id = t.from_account_id AND
+    f.amount = -t.amount
+LEFT JOIN entries d ON
+    d.transfer_id = t.id AND
+    d.account_id = t.to_account_id AND
+    d.amount = t.to_amount
+GROUP BY t.id
+HAVING count(DISTINCT f.id) <> 1 OR count(DISTINCT d.id) <> 1
+ORDER BY t.id;
+
+-- name: ListOrphanEntries :many
+-- every entry should be one of the two entries of its transfer:
+-- the from account with the negative amount, or the to account with the to_amount.
+-- the entries without transfer, with the wrong account or amount, and the extra entries of a transfer are reported.
+SELECT
+    e.id AS entry_id,
+    e.account_id,
+    e.amount,
+    e.transfer_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+    t.id IS NULL OR
+    NOT (
+        (e.account_id = t.from_account_id AND e.amount = -t.amount) OR
+        (e.account_id = t.to_account_id AND e.amount = t.to_amount)
+    ) OR
+    EXISTS (
+        SELECT 1 FROM entries x
+        WHERE x.transfer_id = e.transfer_id AND x.account_id = e.account_id AND x.id < e.id
+    )
+ORDER BY e.id;
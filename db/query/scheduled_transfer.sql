@@ -0,0 +1,78 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    cron_expression,
+    interval_seconds,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+-- the schedule is locked, so it is not changed by the owner and the scheduler at the same time
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfersPage :many
+-- keyset pagination like ListAccountsPage
+SELECT * FROM scheduled_transfers
+WHERE
+    owner = sqlc.arg(owner) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+    amount = sqlc.arg(amount),
+    status = sqlc.arg(status),
+    next_run_at = sqlc.arg(next_run_at),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetDueScheduledTransferForUpdate :one
+-- the due schedule is locked until the end of the transaction,
+-- SKIP LOCKED lets the other schedulers pick the next one instead of waiting,
+-- so more than one server can run the scheduler at the same time.
+SELECT * FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= sqlc.arg(now)::timestamptz
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: SetScheduledTransferNextRun :one
+UPDATE scheduled_transfers
+SET next_run_at = sqlc.arg(next_run_at), updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    scheduled_at,
+    status,
+    transfer_id,
+    error
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListScheduledTransferRunsPage :many
+SELECT * FROM scheduled_transfer_runs
+WHERE
+    scheduled_transfer_id = sqlc.arg(scheduled_transfer_id) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
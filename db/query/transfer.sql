@@ -2,9 +2,12 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    rounding_residue
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetTransfer :one
@@ -12,10 +15,55 @@ SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
 -- name: ListTransfers :many
+-- all transfers touching the account, sent from or to it
 SELECT * FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2
+    from_account_id = sqlc.arg(account_id) OR
+    to_account_id = sqlc.arg(account_id)
 ORDER BY id
-LIMIT $3
-OFFSET $4;
\ No newline at end of file
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListTransfersBetween :many
+-- transfers between 2 accounts in both directions,
+-- each side of OR uses the index on (from_account_id, to_account_id)
+SELECT * FROM transfers
+WHERE
+    (from_account_id = sqlc.arg(account1_id) AND to_account_id = sqlc.arg(account2_id)) OR
+    (from_account_id = sqlc.arg(account2_id) AND to_account_id = sqlc.arg(account1_id))
+ORDER BY id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListOutgoingTransfers :many
+SELECT * FROM transfers
+WHERE from_account_id = sqlc.arg(account_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListIncomingTransfers :many
+SELECT * FROM transfers
+WHERE to_account_id = sqlc.arg(account_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListAccountTransfers :many
+-- outgoing transfers are sent from the account, incoming transfers are sent to the account
+-- the amount range is compared in the currency of the account,
+-- so to_amount is used for the incoming transfers, it differs from amount for cross-currency transfers.
+SELECT * FROM transfers
+WHERE
+    (
+        (sqlc.arg(outgoing)::boolean AND from_account_id = sqlc.arg(account_id)) OR
+        (sqlc.arg(incoming)::boolean AND to_account_id = sqlc.arg(account_id))
+    ) AND
+    (CASE WHEN to_account_id = sqlc.arg(account_id) THEN to_amount ELSE amount END)
+        BETWEEN sqlc.arg(min_amount)::bigint AND sqlc.arg(max_amount)::bigint AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,57 @@
+-- name: GetTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE account_type = $1 LIMIT 1;
+
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    account_type,
+    max_amount,
+    daily_amount,
+    daily_count
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (account_type) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    daily_count = EXCLUDED.daily_count,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetAccountTransferLimit :one
+SELECT * FROM account_transfer_limits
+WHERE account_id = $1 LIMIT 1;
+
+-- name: UpsertAccountTransferLimit :one
+INSERT INTO account_transfer_limits (
+    account_id,
+    max_amount,
+    daily_amount,
+    daily_count
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (account_id) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    daily_count = EXCLUDED.daily_count,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteAccountTransferLimit :exec
+DELETE FROM account_transfer_limits
+WHERE account_id = $1;
+
+-- name: GetDailyOutgoingTransfers :one
+-- withdrawals to the cash accounts are counted, the fees charged by the bank are not.
+SELECT
+    COALESCE(SUM(t.amount), 0)::bigint AS total_amount,
+    COUNT(*) AS transfer_count
+FROM transfers t
+JOIN accounts a ON a.id = t.to_account_id
+WHERE
+    t.from_account_id = sqlc.arg(account_id) AND
+    t.created_at >= sqlc.arg(since)::timestamptz AND
+    a.owner NOT IN ('interest', 'fees');
@@ -1,13 +1,13 @@
--- -- name: CreateUser :one
--- INSERT INTO users (
---     username,
---     hashed_password,
---     fullname,
---     email
--- ) VALUES (
---     $1, $2, $3, $4
--- ) RETURNING *;
+-- name: CreateUser :one
+INSERT INTO users (
+    username,
+    hashed_password,
+    full_name,
+    email
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
 
--- -- name: GetUser :one
--- SELECT * FROM users
--- WHERE id = $1 LIMIT 1;
\ No newline at end of file
+-- name: GetUser :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1;
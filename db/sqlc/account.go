@@ -0,0 +1,50 @@
+package db
+
+// status of an account, see the migration 000006_add_account_status
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+// accountStatusChanges lists the statuses an account can be changed to
+// a closed account can never be used again
+var accountStatusChanges = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive, AccountStatusClosed},
+}
+
+// CanChangeAccountStatus reports whether the status of an account can be changed from one to the other
+func CanChangeAccountStatus(from string, to string) bool {
+	for _, status := range accountStatusChanges[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CashAccountOwner is the username of the system user, see the migration 000008_add_cash_accounts
+// it owns the cash account of each currency, deposits and withdrawals are transfers with the cash account.
+const CashAccountOwner = "system"
+
+// type of an account, see the migration 000012_add_interest
+// the annual interest rate of each type is in the interest_rates table.
+const (
+	AccountTypeChecking = "checking"
+	AccountTypeSavings  = "savings"
+)
+
+// InterestExpenseOwner is the username of the user which owns the interest expense account of each currency,
+// the monthly interest is a transfer from the interest expense account.
+const InterestExpenseOwner = "interest"
+
+// FeeRevenueOwner is the username of the user which owns the fee revenue account of each currency,
+// see the migration 000013_add_fees
+const FeeRevenueOwner = "fees"
+
+// IsSystemAccount reports whether the account belongs to the bank itself,
+// such accounts are only used by deposits, withdrawals, interest and fees, not by the transfers of users.
+func IsSystemAccount(account Accounts) bool {
+	return account.Owner == CashAccountOwner || account.Owner == InterestExpenseOwner || account.Owner == FeeRevenueOwner
+}
@@ -5,13 +5,47 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const addAccountAccruedInterest = `-- name: AddAccountAccruedInterest :one
+UPDATE accounts
+SET accrued_interest = accrued_interest + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
+`
+
+type AddAccountAccruedInterestParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountAccruedInterest(ctx context.Context, arg AddAccountAccruedInterestParams) (Accounts, error) {
+	row := q.queryRow(ctx, q.addAccountAccruedInterestStmt, addAccountAccruedInterest, arg.Amount, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
 `
 
 type AddAccountBalanceParams struct {
@@ -28,6 +62,43 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const addAccountHeldAmount = `-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
+`
+
+type AddAccountHeldAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Accounts, error) {
+	row := q.queryRow(ctx, q.addAccountHeldAmountStmt, addAccountHeldAmount, arg.Amount, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
 	)
 	return i, err
 }
@@ -37,21 +108,28 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Type     string `json:"type"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error) {
-	row := q.queryRow(ctx, q.createAccountStmt, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.queryRow(ctx, q.createAccountStmt, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+	)
 	var i Accounts
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +137,12 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
 	)
 	return i, err
 }
@@ -74,7 +158,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +171,18 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Accounts, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -106,24 +196,205 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Accounts,
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
 	)
 	return i, err
 }
 
+const getCashAccount = `-- name: GetCashAccount :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
+WHERE owner = 'system' AND currency = $1
+LIMIT 1
+`
+
+// the cash account of the currency, owned by the system user
+func (q *Queries) GetCashAccount(ctx context.Context, currency string) (Accounts, error) {
+	row := q.queryRow(ctx, q.getCashAccountStmt, getCashAccount, currency)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const getFeeRevenueAccount = `-- name: GetFeeRevenueAccount :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
+WHERE owner = 'fees' AND currency = $1
+LIMIT 1
+`
+
+// the fee revenue account of the currency, owned by the fees user
+func (q *Queries) GetFeeRevenueAccount(ctx context.Context, currency string) (Accounts, error) {
+	row := q.queryRow(ctx, q.getFeeRevenueAccountStmt, getFeeRevenueAccount, currency)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const getInterestExpenseAccount = `-- name: GetInterestExpenseAccount :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
+WHERE owner = 'interest' AND currency = $1
+LIMIT 1
+`
+
+// the interest expense account of the currency, owned by the interest user
+func (q *Queries) GetInterestExpenseAccount(ctx context.Context, currency string) (Accounts, error) {
+	row := q.queryRow(ctx, q.getInterestExpenseAccountStmt, getInterestExpenseAccount, currency)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const listAccountCurrencies = `-- name: ListAccountCurrencies :many
+SELECT id, currency FROM accounts
+WHERE id = ANY($1::bigint[])
+`
+
+type ListAccountCurrenciesRow struct {
+	ID       int64  `json:"id"`
+	Currency string `json:"currency"`
+}
+
+// currencies of the accounts, e.g. the counterparties in a page of transfers
+func (q *Queries) ListAccountCurrencies(ctx context.Context, ids []int64) ([]ListAccountCurrenciesRow, error) {
+	rows, err := q.query(ctx, q.listAccountCurrenciesStmt, listAccountCurrencies, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAccountCurrenciesRow
+	for rows.Next() {
+		var i ListAccountCurrenciesRow
+		if err := rows.Scan(&i.ID, &i.Currency); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
+WHERE owner = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2
+LIMIT $2
+OFFSET $3
 `
 
 type ListAccountsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Owner  string `json:"owner"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Accounts, error) {
-	rows, err := q.query(ctx, q.listAccountsStmt, listAccounts, arg.Limit, arg.Offset)
+	rows, err := q.query(ctx, q.listAccountsStmt, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Accounts
+	for rows.Next() {
+		var i Accounts
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.Nickname,
+			&i.HeldAmount,
+			&i.Type,
+			&i.AccruedInterest,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsPage = `-- name: ListAccountsPage :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest FROM accounts
+WHERE
+    owner = $1 AND
+    (created_at, id) > ($2::timestamptz, $3::bigint)
+ORDER BY created_at, id
+LIMIT $5
+OFFSET $4
+`
+
+type ListAccountsPageParams struct {
+	Owner          string    `json:"owner"`
+	AfterCreatedAt time.Time `json:"afterCreatedAt"`
+	AfterID        int64     `json:"afterID"`
+	PageOffset     int32     `json:"pageOffset"`
+	PageLimit      int32     `json:"pageLimit"`
+}
+
+// keyset pagination, only the accounts after the cursor (created_at, id) are listed.
+// page_offset is only used by the old clients with page_id, it is 0 for the cursor.
+func (q *Queries) ListAccountsPage(ctx context.Context, arg ListAccountsPageParams) ([]Accounts, error) {
+	rows, err := q.query(ctx, q.listAccountsPageStmt, listAccountsPage,
+		arg.Owner,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +408,12 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.Nickname,
+			&i.HeldAmount,
+			&i.Type,
+			&i.AccruedInterest,
 		); err != nil {
 			return nil, err
 		}
@@ -151,11 +428,73 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
+const setAccountOverdraftLimit = `-- name: SetAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
+`
+
+type SetAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraftLimit"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) SetAccountOverdraftLimit(ctx context.Context, arg SetAccountOverdraftLimitParams) (Accounts, error) {
+	row := q.queryRow(ctx, q.setAccountOverdraftLimitStmt, setAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const setAccountStatus = `-- name: SetAccountStatus :one
+UPDATE accounts
+SET status = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
+`
+
+type SetAccountStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Accounts, error) {
+	row := q.queryRow(ctx, q.setAccountStatusStmt, setAccountStatus, arg.Status, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
 `
 
 type UpdateAccountParams struct {
@@ -172,6 +511,43 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
+	)
+	return i, err
+}
+
+const updateAccountNickname = `-- name: UpdateAccountNickname :one
+UPDATE accounts
+SET nickname = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, nickname, held_amount, type, accrued_interest
+`
+
+type UpdateAccountNicknameParams struct {
+	Nickname string `json:"nickname"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Accounts, error) {
+	row := q.queryRow(ctx, q.updateAccountNicknameStmt, updateAccountNickname, arg.Nickname, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.Nickname,
+		&i.HeldAmount,
+		&i.Type,
+		&i.AccruedInterest,
 	)
 	return i, err
 }
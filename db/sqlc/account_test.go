@@ -47,14 +47,22 @@ import (
 // }
 
 func createRandomAccount(t *testing.T) Accounts {
+	return createRandomAccountWithCurrency(t, util.RandomCurrency())
+}
+
+// TransferTx needs 2 accounts with the same currency if there is no exchange rate
+func createRandomAccountWithCurrency(t *testing.T, currency string) Accounts {
+	// owner of account is a foreign key to users table, so create a user first
+	user := createRandomUser(t)
 	// according account.sql.go file to write unit test
 	arg := CreateAccountParams{
 		//Owner:    "Erin", // randomly generated ?
-		Owner: util.RandomOwner(),
+		Owner: user.Username,
 		//Balance:  1000,
 		Balance: util.RandomMoney(),
 		//Currency: "USD",
-		Currency: util.RandomCurrency(),
+		Currency: currency,
+		Type:     AccountTypeChecking,
 	}
 
 	// then call the testQueried.CreateAccount
@@ -67,6 +75,7 @@ func createRandomAccount(t *testing.T) Accounts {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Type, account.Type)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -94,27 +103,31 @@ func TestGetAccount(t *testing.T) {
 	require.Equal(t, account1.Currency, account2.Currency)
 	// WithinDuration to check that 2 timestamps are different
 	// by at most some delta duration.
-	require.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
+	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
 func TestListAccounts(t *testing.T) {
 	// because it select multiple records
 	// we need to create several account
+	// -------after token------ only the accounts of the owner are listed
+	var lastAccount Accounts
 	for i := 0; i < 10; i++ {
-		createRandomAccount(t)
+		lastAccount = createRandomAccount(t)
 	}
 
 	arg := ListAccountsParams{
+		Owner:  lastAccount.Owner,
 		Limit:  5,
-		Offset: 5,
+		Offset: 0,
 	}
 
 	accounts, err := testQueries.ListAccounts(context.Background(), arg)
 	require.NoError(t, err)
-	require.Len(t, accounts, 5)
+	require.NotEmpty(t, accounts)
 
 	for _, account := range accounts {
 		require.NotEmpty(t, account)
+		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
 
@@ -136,8 +149,101 @@ func TestUpdateAccount(t *testing.T) {
 	require.Equal(t, account1.Currency, account2.Currency)
 	// WithinDuration to check that 2 timestamps are different
 	// by at most some delta duration.
-	require.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
+	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
+
+}
+
+// 用 (created_at, id) 當作 cursor, 取得下一頁
+func TestListAccountsPage(t *testing.T) {
+	user := createRandomUser(t)
+
+	var accounts []Accounts
+	for _, currency := range []string{"USD", "TWD"} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+			Type:     AccountTypeChecking,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	page1, err := testQueries.ListAccountsPage(context.Background(), ListAccountsPageParams{
+		Owner:     user.Username,
+		PageLimit: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, accounts[:1], page1)
 
+	page2, err := testQueries.ListAccountsPage(context.Background(), ListAccountsPageParams{
+		Owner:          user.Username,
+		AfterCreatedAt: page1[0].CreatedAt,
+		AfterID:        page1[0].ID,
+		PageLimit:      1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, accounts[1:], page2)
+
+	page3, err := testQueries.ListAccountsPage(context.Background(), ListAccountsPageParams{
+		Owner:          user.Username,
+		AfterCreatedAt: page2[0].CreatedAt,
+		AfterID:        page2[0].ID,
+		PageLimit:      1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, page3)
+}
+
+func TestUpdateAccountNickname(t *testing.T) {
+	account1 := createRandomAccount(t)
+	require.Empty(t, account1.Nickname)
+
+	account2, err := testQueries.UpdateAccountNickname(context.Background(), UpdateAccountNicknameParams{
+		ID:       account1.ID,
+		Nickname: "saving",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "saving", account2.Nickname)
+	require.Equal(t, account1.Balance, account2.Balance)
+}
+
+// 新帳戶是 active, 只有餘額為 0 的帳戶可以關閉
+func TestSetAccountStatus(t *testing.T) {
+	account1 := createRandomAccount(t)
+	require.Equal(t, AccountStatusActive, account1.Status)
+
+	account2, err := testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+
+	// balance is not zero, checked by accounts_closed_zero_balance constraint
+	_, err = testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusClosed,
+	})
+	require.ErrorIs(t, TranslateError(err), ErrAccountNotEmpty)
+
+	// unknown status, checked by accounts_status_valid constraint
+	_, err = testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account1.ID,
+		Status: "deleted",
+	})
+	require.Equal(t, CheckViolation, ErrorCode(err))
+}
+
+func TestCanChangeAccountStatus(t *testing.T) {
+	require.True(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusFrozen))
+	require.True(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusClosed))
+	require.True(t, CanChangeAccountStatus(AccountStatusFrozen, AccountStatusActive))
+	require.True(t, CanChangeAccountStatus(AccountStatusFrozen, AccountStatusClosed))
+
+	require.False(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusActive))
+	require.False(t, CanChangeAccountStatus(AccountStatusClosed, AccountStatusActive))
+	require.False(t, CanChangeAccountStatus(AccountStatusClosed, AccountStatusFrozen))
+	require.False(t, CanChangeAccountStatus("unknown", AccountStatusActive))
 }
 
 func TestDeleteAccount(t *testing.T) {
@@ -158,3 +264,20 @@ func TestDeleteAccount(t *testing.T) {
 	require.Empty(t, account2)
 
 }
+
+func TestListAccountCurrencies(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	rows, err := testQueries.ListAccountCurrencies(context.Background(), []int64{account1.ID, account2.ID, 0})
+	require.NoError(t, err)
+	// 不存在的帳戶不會回傳
+	require.Len(t, rows, 2)
+
+	currencies := map[int64]string{}
+	for _, row := range rows {
+		currencies[row.ID] = row.Currency
+	}
+	require.Equal(t, account1.Currency, currencies[account1.ID])
+	require.Equal(t, account2.Currency, currencies[account2.ID])
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// only the current balance is stored in accounts, the balance at a time in the past is the sum of the entries before it.
+// summing all entries is slow for an account with millions of entries,
+// so SnapshotBalances saves the balance of every account periodically, e.g. at the beginning of every day,
+// then GetAccountBalanceBefore only sums the entries since the latest snapshot.
+
+// minSnapshotAge is how long the snapshot time must be in the past
+// a transaction which started before the snapshot time but is not committed yet
+// creates entries before the snapshot time, they would be missed by the snapshot.
+const minSnapshotAge = time.Minute
+
+// ErrSnapshotTooRecent is returned if the snapshot time is not old enough
+var ErrSnapshotTooRecent = errors.New("snapshot time is too recent")
+
+// SnapshotBalances saves the balance of all accounts at the time, and returns the number of snapshots created.
+// an account has at most one snapshot at the same time,
+// so it is safe to run it again for the same time after a failure.
+func SnapshotBalances(ctx context.Context, store Store, at time.Time) (int, error) {
+	at = at.UTC()
+	if time.Since(at) < minSnapshotAge {
+		return 0, fmt.Errorf("%w: %s", ErrSnapshotTooRecent, at.Format(time.RFC3339))
+	}
+
+	accountIDs, err := store.ListAccountsToSnapshot(ctx, at)
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+
+	n := 0
+	for _, id := range accountIDs {
+		balance, err := store.GetAccountBalanceBefore(ctx, GetAccountBalanceBeforeParams{
+			AccountID: id,
+			Before:    at,
+		})
+		if err != nil {
+			return n, fmt.Errorf("cannot get balance of account [%d]: %w", id, err)
+		}
+
+		_, err = store.CreateBalanceSnapshot(ctx, CreateBalanceSnapshotParams{
+			AccountID:  id,
+			SnapshotAt: at,
+			Balance:    balance,
+		})
+		// created by another run at the same time
+		if errors.Is(TranslateError(err), ErrUniqueViolation) {
+			continue
+		}
+		if err != nil {
+			return n, fmt.Errorf("cannot create balance snapshot of account [%d]: %w", id, err)
+		}
+		n++
+	}
+	return n, nil
+}
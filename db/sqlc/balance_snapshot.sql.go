@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: balance_snapshot.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createBalanceSnapshot = `-- name: CreateBalanceSnapshot :one
+INSERT INTO balance_snapshots (
+    account_id,
+    snapshot_at,
+    balance
+) VALUES (
+    $1, $2, $3
+) RETURNING account_id, snapshot_at, balance, created_at
+`
+
+type CreateBalanceSnapshotParams struct {
+	AccountID  int64     `json:"accountID"`
+	SnapshotAt time.Time `json:"snapshotAt"`
+	Balance    int64     `json:"balance"`
+}
+
+func (q *Queries) CreateBalanceSnapshot(ctx context.Context, arg CreateBalanceSnapshotParams) (BalanceSnapshots, error) {
+	row := q.queryRow(ctx, q.createBalanceSnapshotStmt, createBalanceSnapshot, arg.AccountID, arg.SnapshotAt, arg.Balance)
+	var i BalanceSnapshots
+	err := row.Scan(
+		&i.AccountID,
+		&i.SnapshotAt,
+		&i.Balance,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAccountsToSnapshot = `-- name: ListAccountsToSnapshot :many
+SELECT a.id FROM accounts a
+WHERE
+    a.created_at < $1::timestamptz AND
+    NOT EXISTS (
+        SELECT 1 FROM balance_snapshots s
+        WHERE s.account_id = a.id AND s.snapshot_at = $1::timestamptz
+    )
+ORDER BY a.id
+`
+
+// the accounts created before the time, which don't have a snapshot at the time yet
+func (q *Queries) ListAccountsToSnapshot(ctx context.Context, snapshotAt time.Time) ([]int64, error) {
+	rows, err := q.query(ctx, q.listAccountsToSnapshotStmt, listAccountsToSnapshot, snapshotAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBalanceSnapshots = `-- name: ListBalanceSnapshots :many
+SELECT account_id, snapshot_at, balance, created_at FROM balance_snapshots
+WHERE account_id = $1
+ORDER BY snapshot_at DESC
+LIMIT $2
+`
+
+type ListBalanceSnapshotsParams struct {
+	AccountID int64 `json:"accountID"`
+	Limit     int32 `json:"limit"`
+}
+
+func (q *Queries) ListBalanceSnapshots(ctx context.Context, arg ListBalanceSnapshotsParams) ([]BalanceSnapshots, error) {
+	rows, err := q.query(ctx, q.listBalanceSnapshotsStmt, listBalanceSnapshots, arg.AccountID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BalanceSnapshots
+	for rows.Next() {
+		var i BalanceSnapshots
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.SnapshotAt,
+			&i.Balance,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
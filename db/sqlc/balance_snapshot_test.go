@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceBeforeWithSnapshot(t *testing.T) {
+	account := createRandomAccount(t)
+
+	entry1 := createRandomEntry(t, account, 10)
+	entry2 := createRandomEntry(t, account, -3)
+	entry3 := createRandomEntry(t, account, 5)
+	end := entry3.CreatedAt.Add(time.Second)
+
+	// 沒有快照, 加總所有 entries
+	balance, err := testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    end,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry1.Amount+entry2.Amount+entry3.Amount, balance)
+
+	// 快照的餘額故意跟 entry1 不一樣, 確定有用到快照, 而且只加總快照之後的 entries
+	snapshot, err := testQueries.CreateBalanceSnapshot(context.Background(), CreateBalanceSnapshotParams{
+		AccountID:  account.ID,
+		SnapshotAt: entry2.CreatedAt,
+		Balance:    1000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, snapshot.AccountID)
+
+	balance, err = testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    end,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1000+entry2.Amount+entry3.Amount, balance)
+
+	// 快照之前的時間不用快照
+	balance, err = testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    entry2.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry1.Amount, balance)
+
+	// 同一個時間只能有一個快照
+	_, err = testQueries.CreateBalanceSnapshot(context.Background(), CreateBalanceSnapshotParams{
+		AccountID:  account.ID,
+		SnapshotAt: entry2.CreatedAt,
+		Balance:    1000,
+	})
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+
+	snapshots, err := testQueries.ListBalanceSnapshots(context.Background(), ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+}
+
+func TestSnapshotBalancesTooRecent(t *testing.T) {
+	// the time is checked before any query
+	_, err := SnapshotBalances(context.Background(), nil, time.Now())
+	require.ErrorIs(t, err, ErrSnapshotTooRecent)
+}
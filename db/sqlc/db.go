@@ -22,115 +22,707 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addAccountAccruedInterestStmt, err = db.PrepareContext(ctx, addAccountAccruedInterest); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAccountAccruedInterest: %w", err)
+	}
 	if q.addAccountBalanceStmt, err = db.PrepareContext(ctx, addAccountBalance); err != nil {
 		return nil, fmt.Errorf("error preparing query AddAccountBalance: %w", err)
 	}
+	if q.addAccountHeldAmountStmt, err = db.PrepareContext(ctx, addAccountHeldAmount); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAccountHeldAmount: %w", err)
+	}
+	if q.blockSessionStmt, err = db.PrepareContext(ctx, blockSession); err != nil {
+		return nil, fmt.Errorf("error preparing query BlockSession: %w", err)
+	}
+	if q.captureHoldStmt, err = db.PrepareContext(ctx, captureHold); err != nil {
+		return nil, fmt.Errorf("error preparing query CaptureHold: %w", err)
+	}
+	if q.claimOutboxEventsStmt, err = db.PrepareContext(ctx, claimOutboxEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimOutboxEvents: %w", err)
+	}
+	if q.countAccountsStmt, err = db.PrepareContext(ctx, countAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query CountAccounts: %w", err)
+	}
+	if q.countTransfersStmt, err = db.PrepareContext(ctx, countTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTransfers: %w", err)
+	}
 	if q.createAccountStmt, err = db.PrepareContext(ctx, createAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAccount: %w", err)
 	}
+	if q.createBalanceSnapshotStmt, err = db.PrepareContext(ctx, createBalanceSnapshot); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBalanceSnapshot: %w", err)
+	}
 	if q.createEntryStmt, err = db.PrepareContext(ctx, createEntry); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateEntry: %w", err)
 	}
+	if q.createFeeRuleStmt, err = db.PrepareContext(ctx, createFeeRule); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateFeeRule: %w", err)
+	}
+	if q.createHoldStmt, err = db.PrepareContext(ctx, createHold); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateHold: %w", err)
+	}
+	if q.createIdempotencyKeyStmt, err = db.PrepareContext(ctx, createIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateIdempotencyKey: %w", err)
+	}
+	if q.createInterestAccrualStmt, err = db.PrepareContext(ctx, createInterestAccrual); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInterestAccrual: %w", err)
+	}
+	if q.createOutboxEventStmt, err = db.PrepareContext(ctx, createOutboxEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOutboxEvent: %w", err)
+	}
+	if q.createScheduledTransferStmt, err = db.PrepareContext(ctx, createScheduledTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateScheduledTransfer: %w", err)
+	}
+	if q.createScheduledTransferRunStmt, err = db.PrepareContext(ctx, createScheduledTransferRun); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateScheduledTransferRun: %w", err)
+	}
+	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
+	}
 	if q.createTransferStmt, err = db.PrepareContext(ctx, createTransfer); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTransfer: %w", err)
 	}
+	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
+	}
 	if q.deleteAccountStmt, err = db.PrepareContext(ctx, deleteAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAccount: %w", err)
 	}
+	if q.deleteAccountTransferLimitStmt, err = db.PrepareContext(ctx, deleteAccountTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAccountTransferLimit: %w", err)
+	}
+	if q.deleteFeeRuleStmt, err = db.PrepareContext(ctx, deleteFeeRule); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFeeRule: %w", err)
+	}
+	if q.expireHoldsStmt, err = db.PrepareContext(ctx, expireHolds); err != nil {
+		return nil, fmt.Errorf("error preparing query ExpireHolds: %w", err)
+	}
 	if q.getAccountStmt, err = db.PrepareContext(ctx, getAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAccount: %w", err)
 	}
+	if q.getAccountBalanceBeforeStmt, err = db.PrepareContext(ctx, getAccountBalanceBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountBalanceBefore: %w", err)
+	}
 	if q.getAccountForUpdateStmt, err = db.PrepareContext(ctx, getAccountForUpdate); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAccountForUpdate: %w", err)
 	}
+	if q.getAccountTransferLimitStmt, err = db.PrepareContext(ctx, getAccountTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountTransferLimit: %w", err)
+	}
+	if q.getCashAccountStmt, err = db.PrepareContext(ctx, getCashAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCashAccount: %w", err)
+	}
+	if q.getDailyOutgoingTransfersStmt, err = db.PrepareContext(ctx, getDailyOutgoingTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDailyOutgoingTransfers: %w", err)
+	}
+	if q.getDueScheduledTransferForUpdateStmt, err = db.PrepareContext(ctx, getDueScheduledTransferForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDueScheduledTransferForUpdate: %w", err)
+	}
 	if q.getEntryStmt, err = db.PrepareContext(ctx, getEntry); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEntry: %w", err)
 	}
+	if q.getExchangeRateStmt, err = db.PrepareContext(ctx, getExchangeRate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetExchangeRate: %w", err)
+	}
+	if q.getFeeRevenueAccountStmt, err = db.PrepareContext(ctx, getFeeRevenueAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeeRevenueAccount: %w", err)
+	}
+	if q.getFeeRuleStmt, err = db.PrepareContext(ctx, getFeeRule); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeeRule: %w", err)
+	}
+	if q.getHoldStmt, err = db.PrepareContext(ctx, getHold); err != nil {
+		return nil, fmt.Errorf("error preparing query GetHold: %w", err)
+	}
+	if q.getHoldForUpdateStmt, err = db.PrepareContext(ctx, getHoldForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetHoldForUpdate: %w", err)
+	}
+	if q.getIdempotencyKeyStmt, err = db.PrepareContext(ctx, getIdempotencyKey); err != nil {
+		return nil, fmt.Errorf("error preparing query GetIdempotencyKey: %w", err)
+	}
+	if q.getInterestExpenseAccountStmt, err = db.PrepareContext(ctx, getInterestExpenseAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInterestExpenseAccount: %w", err)
+	}
+	if q.getInterestRateStmt, err = db.PrepareContext(ctx, getInterestRate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInterestRate: %w", err)
+	}
+	if q.getScheduledTransferStmt, err = db.PrepareContext(ctx, getScheduledTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query GetScheduledTransfer: %w", err)
+	}
+	if q.getScheduledTransferForUpdateStmt, err = db.PrepareContext(ctx, getScheduledTransferForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetScheduledTransferForUpdate: %w", err)
+	}
+	if q.getSessionStmt, err = db.PrepareContext(ctx, getSession); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSession: %w", err)
+	}
 	if q.getTransferStmt, err = db.PrepareContext(ctx, getTransfer); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTransfer: %w", err)
 	}
+	if q.getTransferLimitStmt, err = db.PrepareContext(ctx, getTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransferLimit: %w", err)
+	}
+	if q.getUserStmt, err = db.PrepareContext(ctx, getUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUser: %w", err)
+	}
+	if q.listAccountCurrenciesStmt, err = db.PrepareContext(ctx, listAccountCurrencies); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountCurrencies: %w", err)
+	}
+	if q.listAccountEntriesStmt, err = db.PrepareContext(ctx, listAccountEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountEntries: %w", err)
+	}
+	if q.listAccountTransfersStmt, err = db.PrepareContext(ctx, listAccountTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountTransfers: %w", err)
+	}
 	if q.listAccountsStmt, err = db.PrepareContext(ctx, listAccounts); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAccounts: %w", err)
 	}
+	if q.listAccountsPageStmt, err = db.PrepareContext(ctx, listAccountsPage); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountsPage: %w", err)
+	}
+	if q.listAccountsToAccrueInterestStmt, err = db.PrepareContext(ctx, listAccountsToAccrueInterest); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountsToAccrueInterest: %w", err)
+	}
+	if q.listAccountsToPayInterestStmt, err = db.PrepareContext(ctx, listAccountsToPayInterest); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountsToPayInterest: %w", err)
+	}
+	if q.listAccountsToSnapshotStmt, err = db.PrepareContext(ctx, listAccountsToSnapshot); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountsToSnapshot: %w", err)
+	}
+	if q.listBalanceMismatchesStmt, err = db.PrepareContext(ctx, listBalanceMismatches); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBalanceMismatches: %w", err)
+	}
+	if q.listBalanceSnapshotsStmt, err = db.PrepareContext(ctx, listBalanceSnapshots); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBalanceSnapshots: %w", err)
+	}
 	if q.listEntriesStmt, err = db.PrepareContext(ctx, listEntries); err != nil {
 		return nil, fmt.Errorf("error preparing query ListEntries: %w", err)
 	}
+	if q.listEntriesBetweenStmt, err = db.PrepareContext(ctx, listEntriesBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEntriesBetween: %w", err)
+	}
+	if q.listExchangeRatesStmt, err = db.PrepareContext(ctx, listExchangeRates); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExchangeRates: %w", err)
+	}
+	if q.listFeeRulesStmt, err = db.PrepareContext(ctx, listFeeRules); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFeeRules: %w", err)
+	}
+	if q.listIncomingTransfersStmt, err = db.PrepareContext(ctx, listIncomingTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListIncomingTransfers: %w", err)
+	}
+	if q.listInterestAccrualsStmt, err = db.PrepareContext(ctx, listInterestAccruals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInterestAccruals: %w", err)
+	}
+	if q.listInterestRatesStmt, err = db.PrepareContext(ctx, listInterestRates); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInterestRates: %w", err)
+	}
+	if q.listOrphanEntriesStmt, err = db.PrepareContext(ctx, listOrphanEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOrphanEntries: %w", err)
+	}
+	if q.listOutboxEventsByAggregateStmt, err = db.PrepareContext(ctx, listOutboxEventsByAggregate); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOutboxEventsByAggregate: %w", err)
+	}
+	if q.listOutgoingTransfersStmt, err = db.PrepareContext(ctx, listOutgoingTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOutgoingTransfers: %w", err)
+	}
+	if q.listScheduledTransferRunsPageStmt, err = db.PrepareContext(ctx, listScheduledTransferRunsPage); err != nil {
+		return nil, fmt.Errorf("error preparing query ListScheduledTransferRunsPage: %w", err)
+	}
+	if q.listScheduledTransfersPageStmt, err = db.PrepareContext(ctx, listScheduledTransfersPage); err != nil {
+		return nil, fmt.Errorf("error preparing query ListScheduledTransfersPage: %w", err)
+	}
 	if q.listTransfersStmt, err = db.PrepareContext(ctx, listTransfers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTransfers: %w", err)
 	}
+	if q.listTransfersBetweenStmt, err = db.PrepareContext(ctx, listTransfersBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTransfersBetween: %w", err)
+	}
+	if q.listUnmatchedTransfersStmt, err = db.PrepareContext(ctx, listUnmatchedTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnmatchedTransfers: %w", err)
+	}
+	if q.markOutboxEventFailedStmt, err = db.PrepareContext(ctx, markOutboxEventFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxEventFailed: %w", err)
+	}
+	if q.markOutboxEventPublishedStmt, err = db.PrepareContext(ctx, markOutboxEventPublished); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxEventPublished: %w", err)
+	}
+	if q.payInterestAccrualsStmt, err = db.PrepareContext(ctx, payInterestAccruals); err != nil {
+		return nil, fmt.Errorf("error preparing query PayInterestAccruals: %w", err)
+	}
+	if q.releaseOutboxEventsStmt, err = db.PrepareContext(ctx, releaseOutboxEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseOutboxEvents: %w", err)
+	}
+	if q.setAccountOverdraftLimitStmt, err = db.PrepareContext(ctx, setAccountOverdraftLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAccountOverdraftLimit: %w", err)
+	}
+	if q.setAccountStatusStmt, err = db.PrepareContext(ctx, setAccountStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAccountStatus: %w", err)
+	}
+	if q.setHoldStatusStmt, err = db.PrepareContext(ctx, setHoldStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetHoldStatus: %w", err)
+	}
+	if q.setScheduledTransferNextRunStmt, err = db.PrepareContext(ctx, setScheduledTransferNextRun); err != nil {
+		return nil, fmt.Errorf("error preparing query SetScheduledTransferNextRun: %w", err)
+	}
 	if q.updateAccountStmt, err = db.PrepareContext(ctx, updateAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAccount: %w", err)
 	}
+	if q.updateAccountNicknameStmt, err = db.PrepareContext(ctx, updateAccountNickname); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAccountNickname: %w", err)
+	}
+	if q.updateScheduledTransferStmt, err = db.PrepareContext(ctx, updateScheduledTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateScheduledTransfer: %w", err)
+	}
+	if q.upsertAccountTransferLimitStmt, err = db.PrepareContext(ctx, upsertAccountTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertAccountTransferLimit: %w", err)
+	}
+	if q.upsertExchangeRateStmt, err = db.PrepareContext(ctx, upsertExchangeRate); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertExchangeRate: %w", err)
+	}
+	if q.upsertInterestRateStmt, err = db.PrepareContext(ctx, upsertInterestRate); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertInterestRate: %w", err)
+	}
+	if q.upsertTransferLimitStmt, err = db.PrepareContext(ctx, upsertTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertTransferLimit: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addAccountAccruedInterestStmt != nil {
+		if cerr := q.addAccountAccruedInterestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAccountAccruedInterestStmt: %w", cerr)
+		}
+	}
 	if q.addAccountBalanceStmt != nil {
 		if cerr := q.addAccountBalanceStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addAccountBalanceStmt: %w", cerr)
 		}
 	}
+	if q.addAccountHeldAmountStmt != nil {
+		if cerr := q.addAccountHeldAmountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAccountHeldAmountStmt: %w", cerr)
+		}
+	}
+	if q.blockSessionStmt != nil {
+		if cerr := q.blockSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing blockSessionStmt: %w", cerr)
+		}
+	}
+	if q.captureHoldStmt != nil {
+		if cerr := q.captureHoldStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing captureHoldStmt: %w", cerr)
+		}
+	}
+	if q.claimOutboxEventsStmt != nil {
+		if cerr := q.claimOutboxEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimOutboxEventsStmt: %w", cerr)
+		}
+	}
+	if q.countAccountsStmt != nil {
+		if cerr := q.countAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countAccountsStmt: %w", cerr)
+		}
+	}
+	if q.countTransfersStmt != nil {
+		if cerr := q.countTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTransfersStmt: %w", cerr)
+		}
+	}
 	if q.createAccountStmt != nil {
 		if cerr := q.createAccountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAccountStmt: %w", cerr)
 		}
 	}
+	if q.createBalanceSnapshotStmt != nil {
+		if cerr := q.createBalanceSnapshotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBalanceSnapshotStmt: %w", cerr)
+		}
+	}
 	if q.createEntryStmt != nil {
 		if cerr := q.createEntryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createEntryStmt: %w", cerr)
 		}
 	}
+	if q.createFeeRuleStmt != nil {
+		if cerr := q.createFeeRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createFeeRuleStmt: %w", cerr)
+		}
+	}
+	if q.createHoldStmt != nil {
+		if cerr := q.createHoldStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createHoldStmt: %w", cerr)
+		}
+	}
+	if q.createIdempotencyKeyStmt != nil {
+		if cerr := q.createIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createIdempotencyKeyStmt: %w", cerr)
+		}
+	}
+	if q.createInterestAccrualStmt != nil {
+		if cerr := q.createInterestAccrualStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInterestAccrualStmt: %w", cerr)
+		}
+	}
+	if q.createOutboxEventStmt != nil {
+		if cerr := q.createOutboxEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOutboxEventStmt: %w", cerr)
+		}
+	}
+	if q.createScheduledTransferStmt != nil {
+		if cerr := q.createScheduledTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createScheduledTransferStmt: %w", cerr)
+		}
+	}
+	if q.createScheduledTransferRunStmt != nil {
+		if cerr := q.createScheduledTransferRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createScheduledTransferRunStmt: %w", cerr)
+		}
+	}
+	if q.createSessionStmt != nil {
+		if cerr := q.createSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
+		}
+	}
 	if q.createTransferStmt != nil {
 		if cerr := q.createTransferStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTransferStmt: %w", cerr)
 		}
 	}
+	if q.createUserStmt != nil {
+		if cerr := q.createUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+		}
+	}
 	if q.deleteAccountStmt != nil {
 		if cerr := q.deleteAccountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteAccountStmt: %w", cerr)
 		}
 	}
+	if q.deleteAccountTransferLimitStmt != nil {
+		if cerr := q.deleteAccountTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAccountTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.deleteFeeRuleStmt != nil {
+		if cerr := q.deleteFeeRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFeeRuleStmt: %w", cerr)
+		}
+	}
+	if q.expireHoldsStmt != nil {
+		if cerr := q.expireHoldsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing expireHoldsStmt: %w", cerr)
+		}
+	}
 	if q.getAccountStmt != nil {
 		if cerr := q.getAccountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAccountStmt: %w", cerr)
 		}
 	}
+	if q.getAccountBalanceBeforeStmt != nil {
+		if cerr := q.getAccountBalanceBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountBalanceBeforeStmt: %w", cerr)
+		}
+	}
 	if q.getAccountForUpdateStmt != nil {
 		if cerr := q.getAccountForUpdateStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAccountForUpdateStmt: %w", cerr)
 		}
 	}
+	if q.getAccountTransferLimitStmt != nil {
+		if cerr := q.getAccountTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.getCashAccountStmt != nil {
+		if cerr := q.getCashAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCashAccountStmt: %w", cerr)
+		}
+	}
+	if q.getDailyOutgoingTransfersStmt != nil {
+		if cerr := q.getDailyOutgoingTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDailyOutgoingTransfersStmt: %w", cerr)
+		}
+	}
+	if q.getDueScheduledTransferForUpdateStmt != nil {
+		if cerr := q.getDueScheduledTransferForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDueScheduledTransferForUpdateStmt: %w", cerr)
+		}
+	}
 	if q.getEntryStmt != nil {
 		if cerr := q.getEntryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getEntryStmt: %w", cerr)
 		}
 	}
+	if q.getExchangeRateStmt != nil {
+		if cerr := q.getExchangeRateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getExchangeRateStmt: %w", cerr)
+		}
+	}
+	if q.getFeeRevenueAccountStmt != nil {
+		if cerr := q.getFeeRevenueAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeeRevenueAccountStmt: %w", cerr)
+		}
+	}
+	if q.getFeeRuleStmt != nil {
+		if cerr := q.getFeeRuleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeeRuleStmt: %w", cerr)
+		}
+	}
+	if q.getHoldStmt != nil {
+		if cerr := q.getHoldStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getHoldStmt: %w", cerr)
+		}
+	}
+	if q.getHoldForUpdateStmt != nil {
+		if cerr := q.getHoldForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getHoldForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getIdempotencyKeyStmt != nil {
+		if cerr := q.getIdempotencyKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getIdempotencyKeyStmt: %w", cerr)
+		}
+	}
+	if q.getInterestExpenseAccountStmt != nil {
+		if cerr := q.getInterestExpenseAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInterestExpenseAccountStmt: %w", cerr)
+		}
+	}
+	if q.getInterestRateStmt != nil {
+		if cerr := q.getInterestRateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInterestRateStmt: %w", cerr)
+		}
+	}
+	if q.getScheduledTransferStmt != nil {
+		if cerr := q.getScheduledTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getScheduledTransferStmt: %w", cerr)
+		}
+	}
+	if q.getScheduledTransferForUpdateStmt != nil {
+		if cerr := q.getScheduledTransferForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getScheduledTransferForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getSessionStmt != nil {
+		if cerr := q.getSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionStmt: %w", cerr)
+		}
+	}
 	if q.getTransferStmt != nil {
 		if cerr := q.getTransferStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTransferStmt: %w", cerr)
 		}
 	}
+	if q.getTransferLimitStmt != nil {
+		if cerr := q.getTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.getUserStmt != nil {
+		if cerr := q.getUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
+		}
+	}
+	if q.listAccountCurrenciesStmt != nil {
+		if cerr := q.listAccountCurrenciesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountCurrenciesStmt: %w", cerr)
+		}
+	}
+	if q.listAccountEntriesStmt != nil {
+		if cerr := q.listAccountEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountEntriesStmt: %w", cerr)
+		}
+	}
+	if q.listAccountTransfersStmt != nil {
+		if cerr := q.listAccountTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountTransfersStmt: %w", cerr)
+		}
+	}
 	if q.listAccountsStmt != nil {
 		if cerr := q.listAccountsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAccountsStmt: %w", cerr)
 		}
 	}
+	if q.listAccountsPageStmt != nil {
+		if cerr := q.listAccountsPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsPageStmt: %w", cerr)
+		}
+	}
+	if q.listAccountsToAccrueInterestStmt != nil {
+		if cerr := q.listAccountsToAccrueInterestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsToAccrueInterestStmt: %w", cerr)
+		}
+	}
+	if q.listAccountsToPayInterestStmt != nil {
+		if cerr := q.listAccountsToPayInterestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsToPayInterestStmt: %w", cerr)
+		}
+	}
+	if q.listAccountsToSnapshotStmt != nil {
+		if cerr := q.listAccountsToSnapshotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsToSnapshotStmt: %w", cerr)
+		}
+	}
+	if q.listBalanceMismatchesStmt != nil {
+		if cerr := q.listBalanceMismatchesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBalanceMismatchesStmt: %w", cerr)
+		}
+	}
+	if q.listBalanceSnapshotsStmt != nil {
+		if cerr := q.listBalanceSnapshotsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBalanceSnapshotsStmt: %w", cerr)
+		}
+	}
 	if q.listEntriesStmt != nil {
 		if cerr := q.listEntriesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listEntriesStmt: %w", cerr)
 		}
 	}
+	if q.listEntriesBetweenStmt != nil {
+		if cerr := q.listEntriesBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEntriesBetweenStmt: %w", cerr)
+		}
+	}
+	if q.listExchangeRatesStmt != nil {
+		if cerr := q.listExchangeRatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExchangeRatesStmt: %w", cerr)
+		}
+	}
+	if q.listFeeRulesStmt != nil {
+		if cerr := q.listFeeRulesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFeeRulesStmt: %w", cerr)
+		}
+	}
+	if q.listIncomingTransfersStmt != nil {
+		if cerr := q.listIncomingTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listIncomingTransfersStmt: %w", cerr)
+		}
+	}
+	if q.listInterestAccrualsStmt != nil {
+		if cerr := q.listInterestAccrualsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInterestAccrualsStmt: %w", cerr)
+		}
+	}
+	if q.listInterestRatesStmt != nil {
+		if cerr := q.listInterestRatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInterestRatesStmt: %w", cerr)
+		}
+	}
+	if q.listOrphanEntriesStmt != nil {
+		if cerr := q.listOrphanEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOrphanEntriesStmt: %w", cerr)
+		}
+	}
+	if q.listOutboxEventsByAggregateStmt != nil {
+		if cerr := q.listOutboxEventsByAggregateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOutboxEventsByAggregateStmt: %w", cerr)
+		}
+	}
+	if q.listOutgoingTransfersStmt != nil {
+		if cerr := q.listOutgoingTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOutgoingTransfersStmt: %w", cerr)
+		}
+	}
+	if q.listScheduledTransferRunsPageStmt != nil {
+		if cerr := q.listScheduledTransferRunsPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listScheduledTransferRunsPageStmt: %w", cerr)
+		}
+	}
+	if q.listScheduledTransfersPageStmt != nil {
+		if cerr := q.listScheduledTransfersPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listScheduledTransfersPageStmt: %w", cerr)
+		}
+	}
 	if q.listTransfersStmt != nil {
 		if cerr := q.listTransfersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listTransfersStmt: %w", cerr)
 		}
 	}
+	if q.listTransfersBetweenStmt != nil {
+		if cerr := q.listTransfersBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTransfersBetweenStmt: %w", cerr)
+		}
+	}
+	if q.listUnmatchedTransfersStmt != nil {
+		if cerr := q.listUnmatchedTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnmatchedTransfersStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxEventFailedStmt != nil {
+		if cerr := q.markOutboxEventFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxEventFailedStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxEventPublishedStmt != nil {
+		if cerr := q.markOutboxEventPublishedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxEventPublishedStmt: %w", cerr)
+		}
+	}
+	if q.payInterestAccrualsStmt != nil {
+		if cerr := q.payInterestAccrualsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing payInterestAccrualsStmt: %w", cerr)
+		}
+	}
+	if q.releaseOutboxEventsStmt != nil {
+		if cerr := q.releaseOutboxEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseOutboxEventsStmt: %w", cerr)
+		}
+	}
+	if q.setAccountOverdraftLimitStmt != nil {
+		if cerr := q.setAccountOverdraftLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAccountOverdraftLimitStmt: %w", cerr)
+		}
+	}
+	if q.setAccountStatusStmt != nil {
+		if cerr := q.setAccountStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAccountStatusStmt: %w", cerr)
+		}
+	}
+	if q.setHoldStatusStmt != nil {
+		if cerr := q.setHoldStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setHoldStatusStmt: %w", cerr)
+		}
+	}
+	if q.setScheduledTransferNextRunStmt != nil {
+		if cerr := q.setScheduledTransferNextRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setScheduledTransferNextRunStmt: %w", cerr)
+		}
+	}
 	if q.updateAccountStmt != nil {
 		if cerr := q.updateAccountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAccountStmt: %w", cerr)
 		}
 	}
+	if q.updateAccountNicknameStmt != nil {
+		if cerr := q.updateAccountNicknameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAccountNicknameStmt: %w", cerr)
+		}
+	}
+	if q.updateScheduledTransferStmt != nil {
+		if cerr := q.updateScheduledTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateScheduledTransferStmt: %w", cerr)
+		}
+	}
+	if q.upsertAccountTransferLimitStmt != nil {
+		if cerr := q.upsertAccountTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertAccountTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.upsertExchangeRateStmt != nil {
+		if cerr := q.upsertExchangeRateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertExchangeRateStmt: %w", cerr)
+		}
+	}
+	if q.upsertInterestRateStmt != nil {
+		if cerr := q.upsertInterestRateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertInterestRateStmt: %w", cerr)
+		}
+	}
+	if q.upsertTransferLimitStmt != nil {
+		if cerr := q.upsertTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertTransferLimitStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -168,39 +760,187 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                      DBTX
-	tx                      *sql.Tx
-	addAccountBalanceStmt   *sql.Stmt
-	createAccountStmt       *sql.Stmt
-	createEntryStmt         *sql.Stmt
-	createTransferStmt      *sql.Stmt
-	deleteAccountStmt       *sql.Stmt
-	getAccountStmt          *sql.Stmt
-	getAccountForUpdateStmt *sql.Stmt
-	getEntryStmt            *sql.Stmt
-	getTransferStmt         *sql.Stmt
-	listAccountsStmt        *sql.Stmt
-	listEntriesStmt         *sql.Stmt
-	listTransfersStmt       *sql.Stmt
-	updateAccountStmt       *sql.Stmt
+	db                                   DBTX
+	tx                                   *sql.Tx
+	addAccountAccruedInterestStmt        *sql.Stmt
+	addAccountBalanceStmt                *sql.Stmt
+	addAccountHeldAmountStmt             *sql.Stmt
+	blockSessionStmt                     *sql.Stmt
+	captureHoldStmt                      *sql.Stmt
+	claimOutboxEventsStmt                *sql.Stmt
+	countAccountsStmt                    *sql.Stmt
+	countTransfersStmt                   *sql.Stmt
+	createAccountStmt                    *sql.Stmt
+	createBalanceSnapshotStmt            *sql.Stmt
+	createEntryStmt                      *sql.Stmt
+	createFeeRuleStmt                    *sql.Stmt
+	createHoldStmt                       *sql.Stmt
+	createIdempotencyKeyStmt             *sql.Stmt
+	createInterestAccrualStmt            *sql.Stmt
+	createOutboxEventStmt                *sql.Stmt
+	createScheduledTransferStmt          *sql.Stmt
+	createScheduledTransferRunStmt       *sql.Stmt
+	createSessionStmt                    *sql.Stmt
+	createTransferStmt                   *sql.Stmt
+	createUserStmt                       *sql.Stmt
+	deleteAccountStmt                    *sql.Stmt
+	deleteAccountTransferLimitStmt       *sql.Stmt
+	deleteFeeRuleStmt                    *sql.Stmt
+	expireHoldsStmt                      *sql.Stmt
+	getAccountStmt                       *sql.Stmt
+	getAccountBalanceBeforeStmt          *sql.Stmt
+	getAccountForUpdateStmt              *sql.Stmt
+	getAccountTransferLimitStmt          *sql.Stmt
+	getCashAccountStmt                   *sql.Stmt
+	getDailyOutgoingTransfersStmt        *sql.Stmt
+	getDueScheduledTransferForUpdateStmt *sql.Stmt
+	getEntryStmt                         *sql.Stmt
+	getExchangeRateStmt                  *sql.Stmt
+	getFeeRevenueAccountStmt             *sql.Stmt
+	getFeeRuleStmt                       *sql.Stmt
+	getHoldStmt                          *sql.Stmt
+	getHoldForUpdateStmt                 *sql.Stmt
+	getIdempotencyKeyStmt                *sql.Stmt
+	getInterestExpenseAccountStmt        *sql.Stmt
+	getInterestRateStmt                  *sql.Stmt
+	getScheduledTransferStmt             *sql.Stmt
+	getScheduledTransferForUpdateStmt    *sql.Stmt
+	getSessionStmt                       *sql.Stmt
+	getTransferStmt                      *sql.Stmt
+	getTransferLimitStmt                 *sql.Stmt
+	getUserStmt                          *sql.Stmt
+	listAccountCurrenciesStmt            *sql.Stmt
+	listAccountEntriesStmt               *sql.Stmt
+	listAccountTransfersStmt             *sql.Stmt
+	listAccountsStmt                     *sql.Stmt
+	listAccountsPageStmt                 *sql.Stmt
+	listAccountsToAccrueInterestStmt     *sql.Stmt
+	listAccountsToPayInterestStmt        *sql.Stmt
+	listAccountsToSnapshotStmt           *sql.Stmt
+	listBalanceMismatchesStmt            *sql.Stmt
+	listBalanceSnapshotsStmt             *sql.Stmt
+	listEntriesStmt                      *sql.Stmt
+	listEntriesBetweenStmt               *sql.Stmt
+	listExchangeRatesStmt                *sql.Stmt
+	listFeeRulesStmt                     *sql.Stmt
+	listIncomingTransfersStmt            *sql.Stmt
+	listInterestAccrualsStmt             *sql.Stmt
+	listInterestRatesStmt                *sql.Stmt
+	listOrphanEntriesStmt                *sql.Stmt
+	listOutboxEventsByAggregateStmt      *sql.Stmt
+	listOutgoingTransfersStmt            *sql.Stmt
+	listScheduledTransferRunsPageStmt    *sql.Stmt
+	listScheduledTransfersPageStmt       *sql.Stmt
+	listTransfersStmt                    *sql.Stmt
+	listTransfersBetweenStmt             *sql.Stmt
+	listUnmatchedTransfersStmt           *sql.Stmt
+	markOutboxEventFailedStmt            *sql.Stmt
+	markOutboxEventPublishedStmt         *sql.Stmt
+	payInterestAccrualsStmt              *sql.Stmt
+	releaseOutboxEventsStmt              *sql.Stmt
+	setAccountOverdraftLimitStmt         *sql.Stmt
+	setAccountStatusStmt                 *sql.Stmt
+	setHoldStatusStmt                    *sql.Stmt
+	setScheduledTransferNextRunStmt      *sql.Stmt
+	updateAccountStmt                    *sql.Stmt
+	updateAccountNicknameStmt            *sql.Stmt
+	updateScheduledTransferStmt          *sql.Stmt
+	upsertAccountTransferLimitStmt       *sql.Stmt
+	upsertExchangeRateStmt               *sql.Stmt
+	upsertInterestRateStmt               *sql.Stmt
+	upsertTransferLimitStmt              *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                      tx,
-		tx:                      tx,
-		addAccountBalanceStmt:   q.addAccountBalanceStmt,
-		createAccountStmt:       q.createAccountStmt,
-		createEntryStmt:         q.createEntryStmt,
-		createTransferStmt:      q.createTransferStmt,
-		deleteAccountStmt:       q.deleteAccountStmt,
-		getAccountStmt:          q.getAccountStmt,
-		getAccountForUpdateStmt: q.getAccountForUpdateStmt,
-		getEntryStmt:            q.getEntryStmt,
-		getTransferStmt:         q.getTransferStmt,
-		listAccountsStmt:        q.listAccountsStmt,
-		listEntriesStmt:         q.listEntriesStmt,
-		listTransfersStmt:       q.listTransfersStmt,
-		updateAccountStmt:       q.updateAccountStmt,
+		db:                                   tx,
+		tx:                                   tx,
+		addAccountAccruedInterestStmt:        q.addAccountAccruedInterestStmt,
+		addAccountBalanceStmt:                q.addAccountBalanceStmt,
+		addAccountHeldAmountStmt:             q.addAccountHeldAmountStmt,
+		blockSessionStmt:                     q.blockSessionStmt,
+		captureHoldStmt:                      q.captureHoldStmt,
+		claimOutboxEventsStmt:                q.claimOutboxEventsStmt,
+		countAccountsStmt:                    q.countAccountsStmt,
+		countTransfersStmt:                   q.countTransfersStmt,
+		createAccountStmt:                    q.createAccountStmt,
+		createBalanceSnapshotStmt:            q.createBalanceSnapshotStmt,
+		createEntryStmt:                      q.createEntryStmt,
+		createFeeRuleStmt:                    q.createFeeRuleStmt,
+		createHoldStmt:                       q.createHoldStmt,
+		createIdempotencyKeyStmt:             q.createIdempotencyKeyStmt,
+		createInterestAccrualStmt:            q.createInterestAccrualStmt,
+		createOutboxEventStmt:                q.createOutboxEventStmt,
+		createScheduledTransferStmt:          q.createScheduledTransferStmt,
+		createScheduledTransferRunStmt:       q.createScheduledTransferRunStmt,
+		createSessionStmt:                    q.createSessionStmt,
+		createTransferStmt:                   q.createTransferStmt,
+		createUserStmt:                       q.createUserStmt,
+		deleteAccountStmt:                    q.deleteAccountStmt,
+		deleteAccountTransferLimitStmt:       q.deleteAccountTransferLimitStmt,
+		deleteFeeRuleStmt:                    q.deleteFeeRuleStmt,
+		expireHoldsStmt:                      q.expireHoldsStmt,
+		getAccountStmt:                       q.getAccountStmt,
+		getAccountBalanceBeforeStmt:          q.getAccountBalanceBeforeStmt,
+		getAccountForUpdateStmt:              q.getAccountForUpdateStmt,
+		getAccountTransferLimitStmt:          q.getAccountTransferLimitStmt,
+		getCashAccountStmt:                   q.getCashAccountStmt,
+		getDailyOutgoingTransfersStmt:        q.getDailyOutgoingTransfersStmt,
+		getDueScheduledTransferForUpdateStmt: q.getDueScheduledTransferForUpdateStmt,
+		getEntryStmt:                         q.getEntryStmt,
+		getExchangeRateStmt:                  q.getExchangeRateStmt,
+		getFeeRevenueAccountStmt:             q.getFeeRevenueAccountStmt,
+		getFeeRuleStmt:                       q.getFeeRuleStmt,
+		getHoldStmt:                          q.getHoldStmt,
+		getHoldForUpdateStmt:                 q.getHoldForUpdateStmt,
+		getIdempotencyKeyStmt:                q.getIdempotencyKeyStmt,
+		getInterestExpenseAccountStmt:        q.getInterestExpenseAccountStmt,
+		getInterestRateStmt:                  q.getInterestRateStmt,
+		getScheduledTransferStmt:             q.getScheduledTransferStmt,
+		getScheduledTransferForUpdateStmt:    q.getScheduledTransferForUpdateStmt,
+		getSessionStmt:                       q.getSessionStmt,
+		getTransferStmt:                      q.getTransferStmt,
+		getTransferLimitStmt:                 q.getTransferLimitStmt,
+		getUserStmt:                          q.getUserStmt,
+		listAccountCurrenciesStmt:            q.listAccountCurrenciesStmt,
+		listAccountEntriesStmt:               q.listAccountEntriesStmt,
+		listAccountTransfersStmt:             q.listAccountTransfersStmt,
+		listAccountsStmt:                     q.listAccountsStmt,
+		listAccountsPageStmt:                 q.listAccountsPageStmt,
+		listAccountsToAccrueInterestStmt:     q.listAccountsToAccrueInterestStmt,
+		listAccountsToPayInterestStmt:        q.listAccountsToPayInterestStmt,
+		listAccountsToSnapshotStmt:           q.listAccountsToSnapshotStmt,
+		listBalanceMismatchesStmt:            q.listBalanceMismatchesStmt,
+		listBalanceSnapshotsStmt:             q.listBalanceSnapshotsStmt,
+		listEntriesStmt:                      q.listEntriesStmt,
+		listEntriesBetweenStmt:               q.listEntriesBetweenStmt,
+		listExchangeRatesStmt:                q.listExchangeRatesStmt,
+		listFeeRulesStmt:                     q.listFeeRulesStmt,
+		listIncomingTransfersStmt:            q.listIncomingTransfersStmt,
+		listInterestAccrualsStmt:             q.listInterestAccrualsStmt,
+		listInterestRatesStmt:                q.listInterestRatesStmt,
+		listOrphanEntriesStmt:                q.listOrphanEntriesStmt,
+		listOutboxEventsByAggregateStmt:      q.listOutboxEventsByAggregateStmt,
+		listOutgoingTransfersStmt:            q.listOutgoingTransfersStmt,
+		listScheduledTransferRunsPageStmt:    q.listScheduledTransferRunsPageStmt,
+		listScheduledTransfersPageStmt:       q.listScheduledTransfersPageStmt,
+		listTransfersStmt:                    q.listTransfersStmt,
+		listTransfersBetweenStmt:             q.listTransfersBetweenStmt,
+		listUnmatchedTransfersStmt:           q.listUnmatchedTransfersStmt,
+		markOutboxEventFailedStmt:            q.markOutboxEventFailedStmt,
+		markOutboxEventPublishedStmt:         q.markOutboxEventPublishedStmt,
+		payInterestAccrualsStmt:              q.payInterestAccrualsStmt,
+		releaseOutboxEventsStmt:              q.releaseOutboxEventsStmt,
+		setAccountOverdraftLimitStmt:         q.setAccountOverdraftLimitStmt,
+		setAccountStatusStmt:                 q.setAccountStatusStmt,
+		setHoldStatusStmt:                    q.setHoldStatusStmt,
+		setScheduledTransferNextRunStmt:      q.setScheduledTransferNextRunStmt,
+		updateAccountStmt:                    q.updateAccountStmt,
+		updateAccountNicknameStmt:            q.updateAccountNicknameStmt,
+		updateScheduledTransferStmt:          q.updateScheduledTransferStmt,
+		upsertAccountTransferLimitStmt:       q.upsertAccountTransferLimitStmt,
+		upsertExchangeRateStmt:               q.upsertExchangeRateStmt,
+		upsertInterestRateStmt:               q.upsertInterestRateStmt,
+		upsertTransferLimitStmt:              q.upsertTransferLimitStmt,
 	}
 }
@@ -5,36 +5,74 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id
 ) VALUES(
-    $1, $2
-)RETURNING id, account_id, amount, created_at
+    $1, $2, $3
+)RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"accountID"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"accountID"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transferID"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entries, error) {
-	row := q.queryRow(ctx, q.createEntryStmt, createEntry, arg.AccountID, arg.Amount)
+	row := q.queryRow(ctx, q.createEntryStmt, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entries
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const getAccountBalanceBefore = `-- name: GetAccountBalanceBefore :one
+WITH snapshot AS (
+    SELECT snapshot_at, balance FROM balance_snapshots
+    WHERE account_id = $1 AND snapshot_at <= $2::timestamptz
+    ORDER BY snapshot_at DESC
+    LIMIT 1
+)
+SELECT (
+    COALESCE((SELECT balance FROM snapshot), 0) +
+    COALESCE((
+        SELECT SUM(e.amount) FROM entries e
+        WHERE
+            e.account_id = $1 AND
+            e.created_at >= COALESCE((SELECT snapshot_at FROM snapshot), '-infinity'::timestamptz) AND
+            e.created_at < $2::timestamptz
+    ), 0)
+)::bigint AS balance
+`
+
+type GetAccountBalanceBeforeParams struct {
+	AccountID int64     `json:"accountID"`
+	Before    time.Time `json:"before"`
+}
+
+// the balance of the account is always the sum of its entries, see reconcile.sql
+// it is the balance of the latest snapshot before the time, plus the entries since the snapshot.
+func (q *Queries) GetAccountBalanceBefore(ctx context.Context, arg GetAccountBalanceBeforeParams) (int64, error) {
+	row := q.queryRow(ctx, q.getAccountBalanceBeforeStmt, getAccountBalanceBefore, arg.AccountID, arg.Before)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -46,12 +84,87 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entries, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const listAccountEntries = `-- name: ListAccountEntries :many
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE
+    account_id = $1 AND
+    (
+        ($2::boolean AND amount > 0) OR
+        ($3::boolean AND amount < 0)
+    ) AND
+    abs(amount) BETWEEN $4::bigint AND $5::bigint AND
+    created_at >= $6 AND
+    created_at < $7 AND
+    (created_at, id) > ($8::timestamptz, $9::bigint)
+ORDER BY created_at, id
+LIMIT $11
+OFFSET $10
+`
+
+type ListAccountEntriesParams struct {
+	AccountID      int64     `json:"accountID"`
+	Incoming       bool      `json:"incoming"`
+	Outgoing       bool      `json:"outgoing"`
+	MinAmount      int64     `json:"minAmount"`
+	MaxAmount      int64     `json:"maxAmount"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	AfterCreatedAt time.Time `json:"afterCreatedAt"`
+	AfterID        int64     `json:"afterID"`
+	PageOffset     int32     `json:"pageOffset"`
+	PageLimit      int32     `json:"pageLimit"`
+}
+
+// incoming entries have positive amount, outgoing entries have negative amount,
+// the amount range is compared with the absolute value of amount.
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]Entries, error) {
+	rows, err := q.query(ctx, q.listAccountEntriesStmt, listAccountEntries,
+		arg.AccountID,
+		arg.Incoming,
+		arg.Outgoing,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.StartTime,
+		arg.EndTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entries
+	for rows.Next() {
+		var i Entries
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -78,6 +191,51 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesBetween = `-- name: ListEntriesBetween :many
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE
+    account_id = $1 AND
+    created_at >= $2::timestamptz AND
+    created_at < $3::timestamptz
+ORDER BY created_at, id
+`
+
+type ListEntriesBetweenParams struct {
+	AccountID int64     `json:"accountID"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+func (q *Queries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entries, error) {
+	rows, err := q.query(ctx, q.listEntriesBetweenStmt, listEntriesBetween, arg.AccountID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entries
+	for rows.Next() {
+		var i Entries
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomEntry(t *testing.T, account Accounts, amount int64) Entries {
+	arg := CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	}
+
+	entry, err := testQueries.CreateEntry(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, entry)
+
+	require.Equal(t, arg.AccountID, entry.AccountID)
+	require.Equal(t, arg.Amount, entry.Amount)
+	require.NotZero(t, entry.ID)
+	require.NotZero(t, entry.CreatedAt)
+
+	return entry
+}
+
+func TestListAccountEntries(t *testing.T) {
+	account := createRandomAccount(t)
+
+	entry1 := createRandomEntry(t, account, 10)
+	entry2 := createRandomEntry(t, account, -20)
+	entry3 := createRandomEntry(t, account, 30)
+
+	arg := ListAccountEntriesParams{
+		AccountID: account.ID,
+		Incoming:  true,
+		Outgoing:  true,
+		MinAmount: 0,
+		MaxAmount: math.MaxInt64,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+		PageLimit: 10,
+	}
+	entries, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Entries{entry1, entry2, entry3}, entries)
+
+	// 只列出扣款, amount 為負數
+	arg.Incoming = false
+	entries, err = testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Entries{entry2}, entries)
+
+	// the amount range is compared with the absolute value
+	arg.Incoming = true
+	arg.MinAmount = 15
+	arg.MaxAmount = 25
+	entries, err = testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Entries{entry2}, entries)
+}
+
+func TestStatementEntries(t *testing.T) {
+	account := createRandomAccount(t)
+
+	entry1 := createRandomEntry(t, account, 10)
+	entry2 := createRandomEntry(t, account, -3)
+	entry3 := createRandomEntry(t, account, 5)
+
+	// entry2 之前的餘額只有 entry1
+	balance, err := testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    entry2.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry1.Amount, balance)
+
+	// 沒有 entry 的時候是 0
+	balance, err = testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    entry1.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Zero(t, balance)
+
+	entries, err := testQueries.ListEntriesBetween(context.Background(), ListEntriesBetweenParams{
+		AccountID: account.ID,
+		StartTime: entry2.CreatedAt,
+		EndTime:   entry3.CreatedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, entry2.ID, entries[0].ID)
+	require.Equal(t, entry3.ID, entries[1].ID)
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// postgres error codes of constraint violations
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
+)
+
+// Errors returned by the store, the api turns them into 4xx responses
+// the store doesn't return the raw sql.ErrNoRows or pq.Error to caller,
+// but the typed errors below which still wrap the original error.
+var (
+	ErrRecordNotFound            = errors.New("record not found")
+	ErrAccountNotFound           = errors.New("account not found")
+	ErrInsufficientFunds         = errors.New("insufficient funds")
+	ErrCurrencyMismatch          = errors.New("currency mismatch")
+	ErrInvalidAmount             = errors.New("amount must be positive")
+	ErrUnsupportedCurrency       = errors.New("currency is not supported")
+	ErrUniqueViolation           = errors.New("record already exists")
+	ErrForeignKeyViolation       = errors.New("referenced record doesn't exist")
+	ErrAccountNotActive          = errors.New("account is not active")
+	ErrAccountNotEmpty           = errors.New("account balance is not zero")
+	ErrInvalidStatusChange       = errors.New("account status can not be changed")
+	ErrHoldNotFound              = errors.New("hold not found")
+	ErrHoldNotActive             = errors.New("hold is not active")
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+)
+
+// constraintErrors maps the name of the constraint in migration files to the typed error
+var constraintErrors = map[string]error{
+	"accounts_balance_limit":                   ErrInsufficientFunds,
+	"transfers_amount_positive":                ErrInvalidAmount,
+	"accounts_currency_fkey":                   ErrUnsupportedCurrency,
+	"transfers_from_account_id_fkey":           ErrAccountNotFound,
+	"transfers_to_account_id_fkey":             ErrAccountNotFound,
+	"entries_account_id_fkey":                  ErrAccountNotFound,
+	"accounts_closed_zero_balance":             ErrAccountNotEmpty,
+	"transfers_to_amount_positive":             ErrInvalidAmount,
+	"exchange_rates_from_currency_fkey":        ErrUnsupportedCurrency,
+	"exchange_rates_to_currency_fkey":          ErrUnsupportedCurrency,
+	"accounts_available_limit":                 ErrInsufficientFunds,
+	"holds_amount_positive":                    ErrInvalidAmount,
+	"holds_account_id_fkey":                    ErrAccountNotFound,
+	"holds_to_account_id_fkey":                 ErrAccountNotFound,
+	"scheduled_transfers_amount_positive":      ErrInvalidAmount,
+	"scheduled_transfers_one_recurrence":       ErrInvalidSchedule,
+	"scheduled_transfers_from_account_id_fkey": ErrAccountNotFound,
+	"scheduled_transfers_to_account_id_fkey":   ErrAccountNotFound,
+}
+
+// dbError is a typed error which still keeps the original error of the driver,
+// so errors.Is(err, ErrInsufficientFunds) and errors.As(err, &pqErr) both work.
+type dbError struct {
+	kind  error
+	cause error
+}
+
+func (e *dbError) Error() string {
+	return e.kind.Error() + ": " + e.cause.Error()
+}
+
+func (e *dbError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *dbError) Unwrap() error {
+	return e.cause
+}
+
+// ErrorCode returns the postgres error code of err, or empty string if err is not a pq.Error
+func ErrorCode(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+	return ""
+}
+
+// TranslateError converts the error returned by postgres into the typed error
+// sql.ErrNoRows becomes ErrRecordNotFound, and the constraint violations become
+// the error of the constraint, or ErrUniqueViolation / ErrForeignKeyViolation.
+// other errors are returned as they are.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	// already translated
+	var dbErr *dbError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &dbError{kind: ErrRecordNotFound, cause: err}
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	if kind, ok := constraintErrors[pqErr.Constraint]; ok {
+		return &dbError{kind: kind, cause: err}
+	}
+
+	switch string(pqErr.Code) {
+	case UniqueViolation:
+		return &dbError{kind: ErrUniqueViolation, cause: err}
+	case ForeignKeyViolation:
+		return &dbError{kind: ErrForeignKeyViolation, cause: err}
+	}
+	return err
+}
+
+// TranslateNotFound is like TranslateError, but sql.ErrNoRows becomes the given error,
+// e.g. ErrAccountNotFound for the queries of accounts table
+func TranslateNotFound(err error, notFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return &dbError{kind: notFound, cause: err}
+	}
+	return TranslateError(err)
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	err := TranslateError(&pq.Error{Code: CheckViolation, Constraint: "accounts_balance_limit"})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	// the original error of the driver is kept
+	var pqErr *pq.Error
+	require.True(t, errors.As(err, &pqErr))
+	require.Equal(t, CheckViolation, ErrorCode(err))
+
+	err = TranslateError(&pq.Error{Code: CheckViolation, Constraint: "transfers_amount_positive"})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	err = TranslateError(&pq.Error{Code: ForeignKeyViolation, Constraint: "accounts_currency_fkey"})
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	err = TranslateError(&pq.Error{Code: ForeignKeyViolation, Constraint: "transfers_to_account_id_fkey"})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	// unknown constraint becomes the general error of its code
+	err = TranslateError(&pq.Error{Code: ForeignKeyViolation, Constraint: "accounts_owner_fkey"})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
+
+	err = TranslateError(&pq.Error{Code: UniqueViolation, Constraint: "users_pkey"})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+
+	// no rows becomes not found, and the cause is kept
+	err = TranslateError(sql.ErrNoRows)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = TranslateNotFound(sql.ErrNoRows, ErrAccountNotFound)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+	require.NotErrorIs(t, err, ErrRecordNotFound)
+
+	// translate twice doesn't change the error
+	require.Equal(t, err, TranslateError(err))
+
+	// other errors are not changed
+	require.Equal(t, sql.ErrConnDone, TranslateError(sql.ErrConnDone))
+	require.Nil(t, TranslateError(nil))
+	require.Empty(t, ErrorCode(sql.ErrNoRows))
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidExchangeRate is returned if the rate is not a positive decimal number
+var ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+
+// exchange rate is kept as decimal string, the same as numeric column returned by postgres,
+// and it is calculated by big.Rat, so no precision is lost by float.
+var exchangeRatePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ParseExchangeRate checks the rate is a positive decimal number, e.g. "30.25"
+func ParseExchangeRate(rate string) (*big.Rat, error) {
+	if !exchangeRatePattern.MatchString(rate) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExchangeRate, rate)
+	}
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExchangeRate, rate)
+	}
+	return r, nil
+}
+
+// convertAmount converts amount by rate, the result is rounded down,
+// so the bank never credits more than the exact value.
+// residue is the fraction which is not credited, it has the same decimal places as rate.
+func convertAmount(amount int64, rate string) (toAmount int64, residue string, err error) {
+	r, err := ParseExchangeRate(rate)
+	if err != nil {
+		return 0, "", err
+	}
+
+	exact := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	// Quo of big.Int truncates toward zero, it is rounding down for positive numbers
+	rounded := new(big.Int).Quo(exact.Num(), exact.Denom())
+	if !rounded.IsInt64() {
+		return 0, "", fmt.Errorf("%w: converted amount is too large", ErrInvalidAmount)
+	}
+	toAmount = rounded.Int64()
+	if toAmount <= 0 {
+		return 0, "", fmt.Errorf("%w: %d is converted to zero by rate %s", ErrInvalidAmount, amount, rate)
+	}
+
+	places := 0
+	if i := strings.IndexByte(rate, '.'); i >= 0 {
+		places = len(rate) - i - 1
+	}
+	fraction := new(big.Rat).Sub(exact, new(big.Rat).SetInt(rounded))
+	return toAmount, fraction.FloatString(places), nil
+}
@@ -0,0 +1,95 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: exchange_rate.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getExchangeRate = `-- name: GetExchangeRate :one
+SELECT from_currency, to_currency, rate, updated_at FROM exchange_rates
+WHERE from_currency = $1 AND to_currency = $2
+LIMIT 1
+`
+
+type GetExchangeRateParams struct {
+	FromCurrency string `json:"fromCurrency"`
+	ToCurrency   string `json:"toCurrency"`
+}
+
+func (q *Queries) GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRates, error) {
+	row := q.queryRow(ctx, q.getExchangeRateStmt, getExchangeRate, arg.FromCurrency, arg.ToCurrency)
+	var i ExchangeRates
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listExchangeRates = `-- name: ListExchangeRates :many
+SELECT from_currency, to_currency, rate, updated_at FROM exchange_rates
+ORDER BY from_currency, to_currency
+`
+
+func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRates, error) {
+	rows, err := q.query(ctx, q.listExchangeRatesStmt, listExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRates
+	for rows.Next() {
+		var i ExchangeRates
+		if err := rows.Scan(
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.Rate,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (
+    from_currency,
+    to_currency,
+    rate
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (from_currency, to_currency) DO UPDATE
+SET rate = EXCLUDED.rate, updated_at = now()
+RETURNING from_currency, to_currency, rate, updated_at
+`
+
+type UpsertExchangeRateParams struct {
+	FromCurrency string `json:"fromCurrency"`
+	ToCurrency   string `json:"toCurrency"`
+	Rate         string `json:"rate"`
+}
+
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRates, error) {
+	row := q.queryRow(ctx, q.upsertExchangeRateStmt, upsertExchangeRate, arg.FromCurrency, arg.ToCurrency, arg.Rate)
+	var i ExchangeRates
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAmount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   int64
+		rate     string
+		toAmount int64
+		residue  string
+		err      error
+	}{
+		{name: "Integer", amount: 100, rate: "30", toAmount: 3000, residue: "0"},
+		{name: "RoundDown", amount: 1000, rate: "0.0331", toAmount: 33, residue: "0.1000"},
+		{name: "NoResidue", amount: 200, rate: "0.5", toAmount: 100, residue: "0.0"},
+		{name: "ZeroAmount", amount: 10, rate: "0.01", err: ErrInvalidAmount},
+		{name: "Overflow", amount: 1 << 62, rate: "4", err: ErrInvalidAmount},
+		{name: "ZeroRate", amount: 10, rate: "0", err: ErrInvalidExchangeRate},
+		{name: "NegativeRate", amount: 10, rate: "-1.5", err: ErrInvalidExchangeRate},
+		{name: "Fraction", amount: 10, rate: "1/3", err: ErrInvalidExchangeRate},
+		{name: "Exponent", amount: 10, rate: "1e3", err: ErrInvalidExchangeRate},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			toAmount, residue, err := convertAmount(tc.amount, tc.rate)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.toAmount, toAmount)
+			require.Equal(t, tc.residue, residue)
+		})
+	}
+}
+
+func TestUpsertExchangeRate(t *testing.T) {
+	arg := UpsertExchangeRateParams{
+		FromCurrency: "USD",
+		ToCurrency:   "TWD",
+		Rate:         "30.5",
+	}
+	rate1, err := testQueries.UpsertExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FromCurrency, rate1.FromCurrency)
+	require.Equal(t, arg.ToCurrency, rate1.ToCurrency)
+	require.Equal(t, arg.Rate, rate1.Rate)
+
+	// 同一組幣別再寫入一次就是更新
+	arg.Rate = "31.25"
+	rate2, err := testQueries.UpsertExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Rate, rate2.Rate)
+
+	rate3, err := testQueries.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		FromCurrency: arg.FromCurrency,
+		ToCurrency:   arg.ToCurrency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rate2, rate3)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+)
+
+// fees of transfers:
+// the fee is charged in the currency of the from account, by the rules of the fee schedule.
+// the rules of a currency are the tiers of the amount, the rule with the largest MinAmount
+// which is not more than the amount is used, see the migration 000013_add_fees.
+// the fee is a separate transfer from the from account to the fee revenue account,
+// within the same transaction as the transfer, so the ledger is still balanced.
+
+// ErrInvalidFeeRule is returned if a rule of the fee schedule can not be used
+var ErrInvalidFeeRule = errors.New("invalid fee rule")
+
+// FeeSchedule decides the fee of a transfer
+type FeeSchedule interface {
+	// TransferFee returns the fee of the amount in the currency, 0 means no fee.
+	// q is the querier of the transfer transaction, so the rules are read within the transaction.
+	TransferFee(ctx context.Context, q Querier, currency string, amount int64) (int64, error)
+}
+
+// ComputeFee returns the fee of the amount by the rule:
+// FlatFee + Amount * Percentage, rounded half to even, then raised to MinFee and capped by MaxFee.
+// MaxFee 0 means no cap.
+func ComputeFee(rule FeeRules, amount int64) (int64, error) {
+	percentage, ok := new(big.Rat).SetString(rule.Percentage)
+	if !ok || percentage.Sign() < 0 || rule.FlatFee < 0 || rule.MinFee < 0 || rule.MaxFee < 0 {
+		return 0, fmt.Errorf("%w: rule [%d] of %s", ErrInvalidFeeRule, rule.ID, rule.Currency)
+	}
+
+	exact := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), percentage)
+	fee := roundHalfEven(exact)
+	fee.Add(fee, big.NewInt(rule.FlatFee))
+	if fee.Cmp(big.NewInt(rule.MinFee)) < 0 {
+		fee.SetInt64(rule.MinFee)
+	}
+	if rule.MaxFee > 0 && fee.Cmp(big.NewInt(rule.MaxFee)) > 0 {
+		fee.SetInt64(rule.MaxFee)
+	}
+	if !fee.IsInt64() {
+		return 0, fmt.Errorf("%w: fee is too large", ErrInvalidAmount)
+	}
+	return fee.Int64(), nil
+}
+
+// DBFeeSchedule is a FeeSchedule with the rules kept in the fee_rules table
+// it is the default of NewStore, the rules can be changed without restarting the server.
+type DBFeeSchedule struct{}
+
+// TransferFee returns the fee by the rule of the amount in db
+func (DBFeeSchedule) TransferFee(ctx context.Context, q Querier, currency string, amount int64) (int64, error) {
+	rule, err := q.GetFeeRule(ctx, GetFeeRuleParams{
+		Currency: currency,
+		Amount:   amount,
+	})
+	if err != nil {
+		// no rule, no fee
+		if errors.Is(TranslateError(err), ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ComputeFee(rule, amount)
+}
+
+// StaticFeeSchedule is a FeeSchedule with fixed rules, e.g. loaded from the config
+type StaticFeeSchedule struct {
+	// rules of each currency, sorted by MinAmount
+	rules map[string][]FeeRules
+}
+
+// NewStaticFeeSchedule creates a new StaticFeeSchedule, the rules are checked once here
+func NewStaticFeeSchedule(rules []FeeRules) (*StaticFeeSchedule, error) {
+	schedule := &StaticFeeSchedule{
+		rules: make(map[string][]FeeRules),
+	}
+	for _, rule := range rules {
+		if rule.Currency == "" || rule.MinAmount < 0 {
+			return nil, fmt.Errorf("%w: rule [%d] of %q", ErrInvalidFeeRule, rule.ID, rule.Currency)
+		}
+		if rule.Percentage == "" {
+			rule.Percentage = "0"
+		}
+		if _, err := ComputeFee(rule, 0); err != nil {
+			return nil, err
+		}
+		schedule.rules[rule.Currency] = append(schedule.rules[rule.Currency], rule)
+	}
+
+	for currency, rules := range schedule.rules {
+		sort.Slice(rules, func(i, j int) bool { return rules[i].MinAmount < rules[j].MinAmount })
+		for i := 1; i < len(rules); i++ {
+			if rules[i].MinAmount == rules[i-1].MinAmount {
+				return nil, fmt.Errorf("%w: duplicate min amount %d of %s", ErrInvalidFeeRule, rules[i].MinAmount, currency)
+			}
+		}
+	}
+	return schedule, nil
+}
+
+// TransferFee returns the fee by the rule of the amount
+func (schedule *StaticFeeSchedule) TransferFee(ctx context.Context, q Querier, currency string, amount int64) (int64, error) {
+	rules := schedule.rules[currency]
+	// the first rule with MinAmount more than the amount, the rule before it is the tier of the amount
+	i := sort.Search(len(rules), func(i int) bool { return rules[i].MinAmount > amount })
+	if i == 0 {
+		return 0, nil
+	}
+	return ComputeFee(rules[i-1], amount)
+}
+
+// LoadFeeScheduleFile creates a StaticFeeSchedule with the rules in a JSON file
+// the file is read only once, the server must be restarted to use the new rules.
+// percentage must be a string, so no precision is lost by float. e.g.
+//
+//	[
+//	  {"currency": "USD", "flatFee": 30},
+//	  {"currency": "TWD", "percentage": "0.001", "minFee": 10, "maxFee": 1000},
+//	  {"currency": "TWD", "minAmount": 10000000, "percentage": "0.0005"}
+//	]
+func LoadFeeScheduleFile(path string) (*StaticFeeSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fee schedule file: %w", err)
+	}
+
+	var rules []FeeRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse fee schedule file: %w", err)
+	}
+	return NewStaticFeeSchedule(rules)
+}
@@ -0,0 +1,129 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: fee.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createFeeRule = `-- name: CreateFeeRule :one
+INSERT INTO fee_rules (
+    currency,
+    min_amount,
+    flat_fee,
+    percentage,
+    min_fee,
+    max_fee
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, currency, min_amount, flat_fee, percentage, min_fee, max_fee, created_at
+`
+
+type CreateFeeRuleParams struct {
+	Currency   string `json:"currency"`
+	MinAmount  int64  `json:"minAmount"`
+	FlatFee    int64  `json:"flatFee"`
+	Percentage string `json:"percentage"`
+	MinFee     int64  `json:"minFee"`
+	MaxFee     int64  `json:"maxFee"`
+}
+
+func (q *Queries) CreateFeeRule(ctx context.Context, arg CreateFeeRuleParams) (FeeRules, error) {
+	row := q.queryRow(ctx, q.createFeeRuleStmt, createFeeRule,
+		arg.Currency,
+		arg.MinAmount,
+		arg.FlatFee,
+		arg.Percentage,
+		arg.MinFee,
+		arg.MaxFee,
+	)
+	var i FeeRules
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.MinAmount,
+		&i.FlatFee,
+		&i.Percentage,
+		&i.MinFee,
+		&i.MaxFee,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteFeeRule = `-- name: DeleteFeeRule :exec
+DELETE FROM fee_rules
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeeRule(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteFeeRuleStmt, deleteFeeRule, id)
+	return err
+}
+
+const getFeeRule = `-- name: GetFeeRule :one
+SELECT id, currency, min_amount, flat_fee, percentage, min_fee, max_fee, created_at FROM fee_rules
+WHERE currency = $1 AND min_amount <= $2
+ORDER BY min_amount DESC
+LIMIT 1
+`
+
+type GetFeeRuleParams struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// the tier of the amount
+func (q *Queries) GetFeeRule(ctx context.Context, arg GetFeeRuleParams) (FeeRules, error) {
+	row := q.queryRow(ctx, q.getFeeRuleStmt, getFeeRule, arg.Currency, arg.Amount)
+	var i FeeRules
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.MinAmount,
+		&i.FlatFee,
+		&i.Percentage,
+		&i.MinFee,
+		&i.MaxFee,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listFeeRules = `-- name: ListFeeRules :many
+SELECT id, currency, min_amount, flat_fee, percentage, min_fee, max_fee, created_at FROM fee_rules
+ORDER BY currency, min_amount
+`
+
+func (q *Queries) ListFeeRules(ctx context.Context) ([]FeeRules, error) {
+	rows, err := q.query(ctx, q.listFeeRulesStmt, listFeeRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeeRules
+	for rows.Next() {
+		var i FeeRules
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.MinAmount,
+			&i.FlatFee,
+			&i.Percentage,
+			&i.MinFee,
+			&i.MaxFee,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFee(t *testing.T) {
+	testCases := []struct {
+		name   string
+		rule   FeeRules
+		amount int64
+		fee    int64
+	}{
+		{"Flat", FeeRules{FlatFee: 30, Percentage: "0"}, 100000, 30},
+		// 100000 * 0.001 = 100
+		{"Percentage", FeeRules{Percentage: "0.001"}, 100000, 100},
+		// 12345 * 0.01 = 123.45 -> 123 ; 12350 * 0.01 = 123.5 -> 124
+		{"RoundDown", FeeRules{Percentage: "0.01"}, 12345, 123},
+		{"HalfToEven", FeeRules{Percentage: "0.01"}, 12350, 124},
+		{"FlatAndPercentage", FeeRules{FlatFee: 30, Percentage: "0.001"}, 100000, 130},
+		{"MinFee", FeeRules{Percentage: "0.001", MinFee: 10}, 1000, 10},
+		{"MaxFee", FeeRules{Percentage: "0.001", MaxFee: 1000}, 100000000, 1000},
+		{"NoCap", FeeRules{Percentage: "0.001"}, 100000000, 100000},
+		{"Free", FeeRules{Percentage: "0"}, 100000, 0},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := ComputeFee(tc.rule, tc.amount)
+			require.NoError(t, err)
+			require.Equal(t, tc.fee, fee)
+		})
+	}
+
+	for _, percentage := range []string{"", "abc", "-0.01"} {
+		_, err := ComputeFee(FeeRules{Percentage: percentage}, 1000)
+		require.ErrorIs(t, err, ErrInvalidFeeRule, percentage)
+	}
+}
+
+func TestStaticFeeSchedule(t *testing.T) {
+	// 分級: 未滿 10000 收 30, 10000 以上收 0.1%, USD 收 1 元
+	schedule, err := NewStaticFeeSchedule([]FeeRules{
+		{Currency: "TWD", MinAmount: 10000, Percentage: "0.001", MinFee: 30},
+		{Currency: "TWD", FlatFee: 30},
+		{Currency: "USD", FlatFee: 100},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		currency string
+		amount   int64
+		fee      int64
+	}{
+		{"TWD", 1, 30},
+		{"TWD", 9999, 30},
+		{"TWD", 10000, 30},
+		{"TWD", 100000, 100},
+		{"USD", 100000, 100},
+		// no rule, no fee
+		{"EUR", 100000, 0},
+	}
+	for _, tc := range testCases {
+		fee, err := schedule.TransferFee(context.Background(), nil, tc.currency, tc.amount)
+		require.NoError(t, err)
+		require.Equal(t, tc.fee, fee, "%s %d", tc.currency, tc.amount)
+	}
+
+	_, err = NewStaticFeeSchedule([]FeeRules{{Currency: "TWD"}, {Currency: "TWD", FlatFee: 30}})
+	require.ErrorIs(t, err, ErrInvalidFeeRule)
+	_, err = NewStaticFeeSchedule([]FeeRules{{Currency: "TWD", Percentage: "-1"}})
+	require.ErrorIs(t, err, ErrInvalidFeeRule)
+	_, err = NewStaticFeeSchedule([]FeeRules{{FlatFee: 30}})
+	require.ErrorIs(t, err, ErrInvalidFeeRule)
+}
+
+func TestLoadFeeScheduleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fees.json")
+	err := os.WriteFile(path, []byte(`[{"currency": "TWD", "flatFee": 30, "percentage": "0.001"}]`), 0600)
+	require.NoError(t, err)
+
+	schedule, err := LoadFeeScheduleFile(path)
+	require.NoError(t, err)
+	fee, err := schedule.TransferFee(context.Background(), nil, "TWD", 100000)
+	require.NoError(t, err)
+	require.Equal(t, int64(130), fee)
+
+	// percentage must be a string, so no precision is lost by float
+	err = os.WriteFile(path, []byte(`[{"currency": "TWD", "percentage": 0.001}]`), 0600)
+	require.NoError(t, err)
+	_, err = LoadFeeScheduleFile(path)
+	require.Error(t, err)
+
+	_, err = LoadFeeScheduleFile(filepath.Join(t.TempDir(), "not_found.json"))
+	require.Error(t, err)
+}
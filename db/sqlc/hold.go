@@ -0,0 +1,21 @@
+package db
+
+// status of a hold, see the migration 000010_add_holds
+// only a held hold can be captured, released or expired.
+const (
+	HoldStatusHeld     = "held"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+// AvailableBalance is the balance which can be sent out, the held amount is reserved by holds
+func AvailableBalance(account Accounts) int64 {
+	return account.Balance - account.HeldAmount
+}
+
+// SpendableBalance is the most money which can be sent out, the available balance plus the overdraft limit,
+// the same as the constraint accounts_available_limit in db.
+func SpendableBalance(account Accounts) int64 {
+	return AvailableBalance(account) + account.OverdraftLimit
+}
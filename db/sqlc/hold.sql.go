@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: hold.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const captureHold = `-- name: CaptureHold :one
+UPDATE holds
+SET status = 'captured', transfer_id = $1::bigint, updated_at = now()
+WHERE id = $2
+RETURNING id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type CaptureHoldParams struct {
+	TransferID int64 `json:"transferID"`
+	ID         int64 `json:"id"`
+}
+
+func (q *Queries) CaptureHold(ctx context.Context, arg CaptureHoldParams) (Holds, error) {
+	row := q.queryRow(ctx, q.captureHoldStmt, captureHold, arg.TransferID, arg.ID)
+	var i Holds
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type CreateHoldParams struct {
+	AccountID   int64     `json:"accountID"`
+	ToAccountID int64     `json:"toAccountID"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Holds, error) {
+	row := q.queryRow(ctx, q.createHoldStmt, createHold,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	var i Holds
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const expireHolds = `-- name: ExpireHolds :many
+UPDATE holds
+SET status = 'expired', updated_at = now()
+WHERE status = 'held' AND expires_at <= now()
+RETURNING id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+// the expired holds are locked until the end of the transaction,
+// then the held_amount of their accounts is released.
+func (q *Queries) ExpireHolds(ctx context.Context) ([]Holds, error) {
+	rows, err := q.query(ctx, q.expireHoldsStmt, expireHolds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Holds
+	for rows.Next() {
+		var i Holds
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id int64) (Holds, error) {
+	row := q.queryRow(ctx, q.getHoldStmt, getHold, id)
+	var i Holds
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id int64) (Holds, error) {
+	row := q.queryRow(ctx, q.getHoldForUpdateStmt, getHoldForUpdate, id)
+	var i Holds
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setHoldStatus = `-- name: SetHoldStatus :one
+UPDATE holds
+SET status = $1, updated_at = now()
+WHERE id = $2
+RETURNING id, account_id, to_account_id, amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type SetHoldStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetHoldStatus(ctx context.Context, arg SetHoldStatusParams) (Holds, error) {
+	row := q.queryRow(ctx, q.setHoldStatusStmt, setHoldStatus, arg.Status, arg.ID)
+	var i Holds
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
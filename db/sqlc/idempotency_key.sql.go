@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    username,
+    key,
+    request_hash,
+    response_status,
+    response_body
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING username, key, request_hash, response_status, response_body, created_at
+`
+
+type CreateIdempotencyKeyParams struct {
+	Username       string          `json:"username"`
+	Key            string          `json:"key"`
+	RequestHash    string          `json:"requestHash"`
+	ResponseStatus int32           `json:"responseStatus"`
+	ResponseBody   json.RawMessage `json:"responseBody"`
+}
+
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKeys, error) {
+	row := q.queryRow(ctx, q.createIdempotencyKeyStmt, createIdempotencyKey,
+		arg.Username,
+		arg.Key,
+		arg.RequestHash,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+	)
+	var i IdempotencyKeys
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT username, key, request_hash, response_status, response_body, created_at FROM idempotency_keys
+WHERE username = $1 AND key = $2 LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKeys, error) {
+	row := q.queryRow(ctx, q.getIdempotencyKeyStmt, getIdempotencyKey, arg.Username, arg.Key)
+	var i IdempotencyKeys
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bank-demo/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomIdempotencyKey(t *testing.T) IdempotencyKeys {
+	user := createRandomUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username:       user.Username,
+		Key:            util.RandomString(16),
+		RequestHash:    util.RandomString(64),
+		ResponseStatus: http.StatusOK,
+		ResponseBody:   json.RawMessage(`{"amount": 10}`),
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	require.Equal(t, arg.Username, key.Username)
+	require.Equal(t, arg.Key, key.Key)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.Equal(t, arg.ResponseStatus, key.ResponseStatus)
+	require.JSONEq(t, string(arg.ResponseBody), string(key.ResponseBody))
+	require.NotZero(t, key.CreatedAt)
+
+	return key
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	key1 := createRandomIdempotencyKey(t)
+
+	// the same key of the same user can not be saved twice
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:       key1.Username,
+		Key:            key1.Key,
+		RequestHash:    key1.RequestHash,
+		ResponseStatus: key1.ResponseStatus,
+		ResponseBody:   key1.ResponseBody,
+	})
+	require.Error(t, err)
+}
+
+func TestGetIdempotencyKey(t *testing.T) {
+	key1 := createRandomIdempotencyKey(t)
+
+	key2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key1.Username,
+		Key:      key1.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, key1.Username, key2.Username)
+	require.Equal(t, key1.Key, key2.Key)
+	require.Equal(t, key1.RequestHash, key2.RequestHash)
+	require.Equal(t, key1.ResponseStatus, key2.ResponseStatus)
+	require.JSONEq(t, string(key1.ResponseBody), string(key2.ResponseBody))
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// interest of savings accounts:
+// every day, AccrueInterest computes the interest of the day for each account,
+// it is rounded to the minor unit of the currency and added to accounts.accrued_interest.
+// once a month, PayInterest transfers the accrued interest from the interest expense account,
+// so the interest is in the balance and the ledger as a normal transfer.
+
+// daysPerYear is the day count convention of the interest, actual/365 fixed
+const daysPerYear = 365
+
+// ErrInvalidInterestRate is returned if the annual rate is not a non-negative decimal number
+var ErrInvalidInterestRate = errors.New("invalid interest rate")
+
+// DailyInterest returns the interest of the balance for one day, in the minor unit of the currency
+// the exact value balance * annualRate / 365 is rounded half to even (banker's rounding),
+// so the rounding doesn't favor the bank or the customer over many accruals.
+// no interest is paid on negative balance.
+func DailyInterest(balance int64, annualRate string) (int64, error) {
+	rate, ok := new(big.Rat).SetString(annualRate)
+	if !ok || rate.Sign() < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidInterestRate, annualRate)
+	}
+	if balance <= 0 {
+		return 0, nil
+	}
+
+	exact := new(big.Rat).Mul(new(big.Rat).SetInt64(balance), rate)
+	exact.Quo(exact, new(big.Rat).SetInt64(daysPerYear))
+	interest := roundHalfEven(exact)
+	if !interest.IsInt64() {
+		return 0, fmt.Errorf("%w: interest is too large", ErrInvalidAmount)
+	}
+	return interest.Int64(), nil
+}
+
+// roundHalfEven rounds a non-negative number to the nearest integer, and to the even one if it is exactly half way
+func roundHalfEven(x *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	// compare the fraction rem/denom with 1/2
+	switch new(big.Int).Mul(rem, big.NewInt(2)).Cmp(x.Denom()) {
+	case 1:
+		quo.Add(quo, big.NewInt(1))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// AccrueInterest accrues the interest of the date for all accounts which earn interest,
+// and returns the number of accounts accrued.
+// each account is accrued in its own transaction, an account is accrued at most once for a date,
+// so it is safe to run it again for the same date after a failure.
+func AccrueInterest(ctx context.Context, store Store, date time.Time) (int, error) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	accountIDs, err := store.ListAccountsToAccrueInterest(ctx, date)
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+
+	n := 0
+	for _, id := range accountIDs {
+		_, err := store.AccrueInterestTx(ctx, id, date)
+		// accrued by another run at the same time
+		if errors.Is(err, ErrUniqueViolation) {
+			continue
+		}
+		if err != nil {
+			return n, fmt.Errorf("cannot accrue interest of account [%d]: %w", id, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// PayInterest pays the accrued interest of all accounts, and returns the number of accounts paid
+// the interest of a frozen account is kept until it is active again.
+func PayInterest(ctx context.Context, store Store) (int, error) {
+	accountIDs, err := store.ListAccountsToPayInterest(ctx)
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+
+	n := 0
+	for _, id := range accountIDs {
+		result, err := store.PayInterestTx(ctx, id)
+		if errors.Is(err, ErrAccountNotActive) {
+			continue
+		}
+		if err != nil {
+			return n, fmt.Errorf("cannot pay interest of account [%d]: %w", id, err)
+		}
+		if result.Transfer.ID != 0 {
+			n++
+		}
+	}
+	return n, nil
+}
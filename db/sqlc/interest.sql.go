@@ -0,0 +1,240 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: interest.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createInterestAccrual = `-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    annual_rate,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, account_id, accrual_date, balance, annual_rate, amount, transfer_id, created_at
+`
+
+type CreateInterestAccrualParams struct {
+	AccountID   int64     `json:"accountID"`
+	AccrualDate time.Time `json:"accrualDate"`
+	Balance     int64     `json:"balance"`
+	AnnualRate  string    `json:"annualRate"`
+	Amount      int64     `json:"amount"`
+}
+
+func (q *Queries) CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccruals, error) {
+	row := q.queryRow(ctx, q.createInterestAccrualStmt, createInterestAccrual,
+		arg.AccountID,
+		arg.AccrualDate,
+		arg.Balance,
+		arg.AnnualRate,
+		arg.Amount,
+	)
+	var i InterestAccruals
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.AccrualDate,
+		&i.Balance,
+		&i.AnnualRate,
+		&i.Amount,
+		&i.TransferID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInterestRate = `-- name: GetInterestRate :one
+SELECT account_type, annual_rate, updated_at FROM interest_rates
+WHERE account_type = $1 LIMIT 1
+`
+
+func (q *Queries) GetInterestRate(ctx context.Context, accountType string) (InterestRates, error) {
+	row := q.queryRow(ctx, q.getInterestRateStmt, getInterestRate, accountType)
+	var i InterestRates
+	err := row.Scan(&i.AccountType, &i.AnnualRate, &i.UpdatedAt)
+	return i, err
+}
+
+const listAccountsToAccrueInterest = `-- name: ListAccountsToAccrueInterest :many
+SELECT a.id FROM accounts a
+JOIN interest_rates r ON r.account_type = a.type
+WHERE
+    a.status = 'active' AND
+    a.balance > 0 AND
+    r.annual_rate > 0 AND
+    NOT EXISTS (
+        SELECT 1 FROM interest_accruals i
+        WHERE i.account_id = a.id AND i.accrual_date = $1::date
+    )
+ORDER BY a.id
+`
+
+// the active accounts with positive balance and rate, which are not accrued on the date yet
+func (q *Queries) ListAccountsToAccrueInterest(ctx context.Context, accrualDate time.Time) ([]int64, error) {
+	rows, err := q.query(ctx, q.listAccountsToAccrueInterestStmt, listAccountsToAccrueInterest, accrualDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsToPayInterest = `-- name: ListAccountsToPayInterest :many
+SELECT id FROM accounts
+WHERE accrued_interest > 0
+ORDER BY id
+`
+
+func (q *Queries) ListAccountsToPayInterest(ctx context.Context) ([]int64, error) {
+	rows, err := q.query(ctx, q.listAccountsToPayInterestStmt, listAccountsToPayInterest)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestAccruals = `-- name: ListInterestAccruals :many
+SELECT id, account_id, accrual_date, balance, annual_rate, amount, transfer_id, created_at FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date
+`
+
+func (q *Queries) ListInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccruals, error) {
+	rows, err := q.query(ctx, q.listInterestAccrualsStmt, listInterestAccruals, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InterestAccruals
+	for rows.Next() {
+		var i InterestAccruals
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.AnnualRate,
+			&i.Amount,
+			&i.TransferID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestRates = `-- name: ListInterestRates :many
+SELECT account_type, annual_rate, updated_at FROM interest_rates
+ORDER BY account_type
+`
+
+func (q *Queries) ListInterestRates(ctx context.Context) ([]InterestRates, error) {
+	rows, err := q.query(ctx, q.listInterestRatesStmt, listInterestRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InterestRates
+	for rows.Next() {
+		var i InterestRates
+		if err := rows.Scan(&i.AccountType, &i.AnnualRate, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const payInterestAccruals = `-- name: PayInterestAccruals :execrows
+UPDATE interest_accruals
+SET transfer_id = $1::bigint
+WHERE account_id = $2 AND transfer_id IS NULL
+`
+
+type PayInterestAccrualsParams struct {
+	TransferID int64 `json:"transferID"`
+	AccountID  int64 `json:"accountID"`
+}
+
+// the accruals paid by the transfer
+func (q *Queries) PayInterestAccruals(ctx context.Context, arg PayInterestAccrualsParams) (int64, error) {
+	result, err := q.exec(ctx, q.payInterestAccrualsStmt, payInterestAccruals, arg.TransferID, arg.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertInterestRate = `-- name: UpsertInterestRate :one
+INSERT INTO interest_rates (
+    account_type,
+    annual_rate
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (account_type) DO UPDATE
+SET annual_rate = EXCLUDED.annual_rate, updated_at = now()
+RETURNING account_type, annual_rate, updated_at
+`
+
+type UpsertInterestRateParams struct {
+	AccountType string `json:"accountType"`
+	AnnualRate  string `json:"annualRate"`
+}
+
+func (q *Queries) UpsertInterestRate(ctx context.Context, arg UpsertInterestRateParams) (InterestRates, error) {
+	row := q.queryRow(ctx, q.upsertInterestRateStmt, upsertInterestRate, arg.AccountType, arg.AnnualRate)
+	var i InterestRates
+	err := row.Scan(&i.AccountType, &i.AnnualRate, &i.UpdatedAt)
+	return i, err
+}
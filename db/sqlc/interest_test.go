@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyInterest(t *testing.T) {
+	testCases := []struct {
+		name       string
+		balance    int64
+		annualRate string
+		interest   int64
+	}{
+		// 1000000 * 0.0365 / 365 = 100
+		{"Exact", 1000000, "0.0365", 100},
+		// 1000000 * 0.015 / 365 = 41.09...
+		{"RoundDown", 1000000, "0.015", 41},
+		// 2000000 * 0.015 / 365 = 82.19...
+		{"RoundDown2", 2000000, "0.015", 82},
+		// 365 * 0.1 / 365 = 0.1
+		{"Small", 365, "0.1", 0},
+		// 1500 * 0.73 / 365 = 3 ; 1250 * 0.73 / 365 = 2.5 -> 2 ; 1750 * 0.73 / 365 = 3.5 -> 4
+		{"NoRounding", 1500, "0.73", 3},
+		{"HalfToEvenDown", 1250, "0.73", 2},
+		{"HalfToEvenUp", 1750, "0.73", 4},
+		// 1260 * 0.73 / 365 = 2.52 -> 3
+		{"MoreThanHalf", 1260, "0.73", 3},
+		{"ZeroRate", 1000000, "0", 0},
+		{"NegativeBalance", -1000000, "0.015", 0},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			interest, err := DailyInterest(tc.balance, tc.annualRate)
+			require.NoError(t, err)
+			require.Equal(t, tc.interest, interest)
+		})
+	}
+
+	for _, rate := range []string{"", "abc", "-0.01"} {
+		_, err := DailyInterest(1000, rate)
+		require.ErrorIs(t, err, ErrInvalidInterestRate, rate)
+	}
+}
@@ -4,23 +4,166 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type AccountTransferLimits struct {
+	AccountID   int64     `json:"accountID"`
+	MaxAmount   int64     `json:"maxAmount"`
+	DailyAmount int64     `json:"dailyAmount"`
+	DailyCount  int64     `json:"dailyCount"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
 type Accounts struct {
-	ID        int64        `json:"id"`
-	Owner     string       `json:"owner"`
-	Balance   int64        `json:"balance"`
-	Currency  string       `json:"currency"`
-	CreatedAt sql.NullTime `json:"createdAt"`
+	ID              int64     `json:"id"`
+	Owner           string    `json:"owner"`
+	Balance         int64     `json:"balance"`
+	Currency        string    `json:"currency"`
+	CreatedAt       time.Time `json:"createdAt"`
+	OverdraftLimit  int64     `json:"overdraftLimit"`
+	Status          string    `json:"status"`
+	Nickname        string    `json:"nickname"`
+	HeldAmount      int64     `json:"heldAmount"`
+	Type            string    `json:"type"`
+	AccruedInterest int64     `json:"accruedInterest"`
+}
+
+type BalanceSnapshots struct {
+	AccountID  int64     `json:"accountID"`
+	SnapshotAt time.Time `json:"snapshotAt"`
+	Balance    int64     `json:"balance"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type Currencies struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
 }
 
 type Entries struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"accountID"`
 	// can be negative or positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"createdAt"`
+	Amount     int64         `json:"amount"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	TransferID sql.NullInt64 `json:"transferID"`
+}
+
+type ExchangeRates struct {
+	FromCurrency string    `json:"fromCurrency"`
+	ToCurrency   string    `json:"toCurrency"`
+	Rate         string    `json:"rate"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type FeeRules struct {
+	ID         int64     `json:"id"`
+	Currency   string    `json:"currency"`
+	MinAmount  int64     `json:"minAmount"`
+	FlatFee    int64     `json:"flatFee"`
+	Percentage string    `json:"percentage"`
+	MinFee     int64     `json:"minFee"`
+	MaxFee     int64     `json:"maxFee"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type Holds struct {
+	ID          int64         `json:"id"`
+	AccountID   int64         `json:"accountID"`
+	ToAccountID int64         `json:"toAccountID"`
+	Amount      int64         `json:"amount"`
+	Status      string        `json:"status"`
+	TransferID  sql.NullInt64 `json:"transferID"`
+	ExpiresAt   time.Time     `json:"expiresAt"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+type IdempotencyKeys struct {
+	Username       string          `json:"username"`
+	Key            string          `json:"key"`
+	RequestHash    string          `json:"requestHash"`
+	ResponseStatus int32           `json:"responseStatus"`
+	ResponseBody   json.RawMessage `json:"responseBody"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+type InterestAccruals struct {
+	ID          int64         `json:"id"`
+	AccountID   int64         `json:"accountID"`
+	AccrualDate time.Time     `json:"accrualDate"`
+	Balance     int64         `json:"balance"`
+	AnnualRate  string        `json:"annualRate"`
+	Amount      int64         `json:"amount"`
+	TransferID  sql.NullInt64 `json:"transferID"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+type InterestRates struct {
+	AccountType string    `json:"accountType"`
+	AnnualRate  string    `json:"annualRate"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type OutboxEvents struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int64           `json:"aggregateID"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	PublishedAt   sql.NullTime    `json:"publishedAt"`
+	Attempts      int32           `json:"attempts"`
+	LastError     string          `json:"lastError"`
+	LockedUntil   sql.NullTime    `json:"lockedUntil"`
+	FailedAt      sql.NullTime    `json:"failedAt"`
+}
+
+type ScheduledTransferRuns struct {
+	ID                  int64         `json:"id"`
+	ScheduledTransferID int64         `json:"scheduledTransferID"`
+	ScheduledAt         time.Time     `json:"scheduledAt"`
+	Status              string        `json:"status"`
+	TransferID          sql.NullInt64 `json:"transferID"`
+	Error               string        `json:"error"`
+	CreatedAt           time.Time     `json:"createdAt"`
+}
+
+type ScheduledTransfers struct {
+	ID              int64     `json:"id"`
+	Owner           string    `json:"owner"`
+	FromAccountID   int64     `json:"fromAccountID"`
+	ToAccountID     int64     `json:"toAccountID"`
+	Amount          int64     `json:"amount"`
+	CronExpression  string    `json:"cronExpression"`
+	IntervalSeconds int64     `json:"intervalSeconds"`
+	Status          string    `json:"status"`
+	NextRunAt       time.Time `json:"nextRunAt"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type Sessions struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"refreshToken"`
+	UserAgent    string    `json:"userAgent"`
+	ClientIp     string    `json:"clientIp"`
+	IsBlocked    bool      `json:"isBlocked"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type TransferLimits struct {
+	AccountType string    `json:"accountType"`
+	MaxAmount   int64     `json:"maxAmount"`
+	DailyAmount int64     `json:"dailyAmount"`
+	DailyCount  int64     `json:"dailyCount"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 type Transfers struct {
@@ -28,6 +171,18 @@ type Transfers struct {
 	FromAccountID int64 `json:"fromAccountID"`
 	ToAccountID   int64 `json:"toAccountID"`
 	// mist be positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"createdAt"`
+	Amount          int64     `json:"amount"`
+	CreatedAt       time.Time `json:"createdAt"`
+	ToAmount        int64     `json:"toAmount"`
+	ExchangeRate    string    `json:"exchangeRate"`
+	RoundingResidue string    `json:"roundingResidue"`
+}
+
+type Users struct {
+	Username          string    `json:"username"`
+	HashedPassword    string    `json:"hashedPassword"`
+	FullName          string    `json:"fullName"`
+	Email             string    `json:"email"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt"`
+	CreatedAt         time.Time `json:"createdAt"`
 }
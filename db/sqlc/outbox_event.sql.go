@@ -0,0 +1,232 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: outbox_event.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+UPDATE outbox_events
+SET locked_until = $1::timestamptz
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE published_at IS NULL AND failed_at IS NULL AND (locked_until IS NULL OR locked_until < now())
+    ORDER BY id
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error, locked_until, failed_at
+`
+
+type ClaimOutboxEventsParams struct {
+	LockedUntil time.Time `json:"lockedUntil"`
+	BatchSize   int32     `json:"batchSize"`
+}
+
+// the unpublished events are leased to the relay until locked_until, another relay skips them.
+// the rows are only locked by the statement itself, the events are published after it is committed.
+// the order of RETURNING is not defined, the caller sorts the events by id.
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvents, error) {
+	rows, err := q.query(ctx, q.claimOutboxEventsStmt, claimOutboxEvents, arg.LockedUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvents
+	for rows.Next() {
+		var i OutboxEvents
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.LockedUntil,
+			&i.FailedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+    aggregate_type,
+    aggregate_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error, locked_until, failed_at
+`
+
+type CreateOutboxEventParams struct {
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int64           `json:"aggregateID"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// most events are written by the triggers in the migration 000016_add_outbox_events
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvents, error) {
+	row := q.queryRow(ctx, q.createOutboxEventStmt, createOutboxEvent,
+		arg.AggregateType,
+		arg.AggregateID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i OutboxEvents
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.CreatedAt,
+		&i.PublishedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.LockedUntil,
+		&i.FailedAt,
+	)
+	return i, err
+}
+
+const listOutboxEventsByAggregate = `-- name: ListOutboxEventsByAggregate :many
+SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error, locked_until, failed_at FROM outbox_events
+WHERE aggregate_type = $1 AND aggregate_id = $2
+ORDER BY id
+`
+
+type ListOutboxEventsByAggregateParams struct {
+	AggregateType string `json:"aggregateType"`
+	AggregateID   int64  `json:"aggregateID"`
+}
+
+func (q *Queries) ListOutboxEventsByAggregate(ctx context.Context, arg ListOutboxEventsByAggregateParams) ([]OutboxEvents, error) {
+	rows, err := q.query(ctx, q.listOutboxEventsByAggregateStmt, listOutboxEventsByAggregate, arg.AggregateType, arg.AggregateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvents
+	for rows.Next() {
+		var i OutboxEvents
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.LockedUntil,
+			&i.FailedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :one
+UPDATE outbox_events
+SET
+    attempts = attempts + 1,
+    last_error = $1,
+    locked_until = NULL,
+    failed_at = CASE WHEN attempts + 1 >= $2::int THEN now() END
+WHERE id = $3
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error, locked_until, failed_at
+`
+
+type MarkOutboxEventFailedParams struct {
+	LastError   string `json:"lastError"`
+	MaxAttempts int32  `json:"maxAttempts"`
+	ID          int64  `json:"id"`
+}
+
+// the event is dead when it has failed max_attempts times, failed_at is set and it is not claimed anymore
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) (OutboxEvents, error) {
+	row := q.queryRow(ctx, q.markOutboxEventFailedStmt, markOutboxEventFailed, arg.LastError, arg.MaxAttempts, arg.ID)
+	var i OutboxEvents
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.CreatedAt,
+		&i.PublishedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.LockedUntil,
+		&i.FailedAt,
+	)
+	return i, err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :one
+UPDATE outbox_events
+SET published_at = now(), attempts = attempts + 1, last_error = '', locked_until = NULL
+WHERE id = $1
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, published_at, attempts, last_error, locked_until, failed_at
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) (OutboxEvents, error) {
+	row := q.queryRow(ctx, q.markOutboxEventPublishedStmt, markOutboxEventPublished, id)
+	var i OutboxEvents
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.CreatedAt,
+		&i.PublishedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.LockedUntil,
+		&i.FailedAt,
+	)
+	return i, err
+}
+
+const releaseOutboxEvents = `-- name: ReleaseOutboxEvents :exec
+UPDATE outbox_events
+SET locked_until = NULL
+WHERE id = ANY($1::bigint[]) AND published_at IS NULL
+`
+
+// the events can be claimed again immediately, instead of waiting for the lease to expire
+func (q *Queries) ReleaseOutboxEvents(ctx context.Context, ids []int64) error {
+	_, err := q.exec(ctx, q.releaseOutboxEventsStmt, releaseOutboxEvents, pq.Array(ids))
+	return err
+}
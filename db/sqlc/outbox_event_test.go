@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func listOutboxEvents(t *testing.T, aggregateType string, aggregateID int64) []OutboxEvents {
+	events, err := testQueries.ListOutboxEventsByAggregate(context.Background(), ListOutboxEventsByAggregateParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+	})
+	require.NoError(t, err)
+	return events
+}
+
+// 帳戶的 events 由 trigger 寫入, 餘額的變動不寫 event
+func TestAccountOutboxEvents(t *testing.T) {
+	account := createRandomAccount(t)
+
+	events := listOutboxEvents(t, "account", account.ID)
+	require.Len(t, events, 1)
+	require.Equal(t, "account.created", events[0].EventType)
+	require.False(t, events[0].PublishedAt.Valid)
+
+	var payload Accounts
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	require.Equal(t, account.ID, payload.ID)
+	require.Equal(t, account.Owner, payload.Owner)
+
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, listOutboxEvents(t, "account", account.ID), 1)
+
+	_, err = testQueries.UpdateAccountNickname(context.Background(), UpdateAccountNicknameParams{
+		ID:       account.ID,
+		Nickname: "saving",
+	})
+	require.NoError(t, err)
+	_, err = testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	events = listOutboxEvents(t, "account", account.ID)
+	require.Len(t, events, 3)
+	require.Equal(t, "account.updated", events[1].EventType)
+	require.Equal(t, "account.status_changed", events[2].EventType)
+	require.NoError(t, json.Unmarshal(events[2].Payload, &payload))
+	require.Equal(t, AccountStatusFrozen, payload.Status)
+}
+
+func TestTransferOutboxEvent(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	transfer := createRandomTransfer(t, account1, account2, 10)
+
+	events := listOutboxEvents(t, "transfer", transfer.ID)
+	require.Len(t, events, 1)
+	require.Equal(t, "transfer.created", events[0].EventType)
+
+	var payload Transfers
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	require.Equal(t, transfer.ID, payload.ID)
+	require.Equal(t, transfer.Amount, payload.Amount)
+}
+
+func TestMarkOutboxEvent(t *testing.T) {
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		AggregateType: "test",
+		AggregateID:   1,
+		EventType:     "test.created",
+		Payload:       json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+	require.Zero(t, event.Attempts)
+
+	// 失敗的 event 保留錯誤訊息, 之後再發布
+	failed, err := testQueries.MarkOutboxEventFailed(context.Background(), MarkOutboxEventFailedParams{
+		ID:          event.ID,
+		LastError:   "connection refused",
+		MaxAttempts: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, "connection refused", failed.LastError)
+	require.False(t, failed.PublishedAt.Valid)
+	require.False(t, failed.FailedAt.Valid)
+
+	published, err := testQueries.MarkOutboxEventPublished(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), published.Attempts)
+	require.Empty(t, published.LastError)
+	require.True(t, published.PublishedAt.Valid)
+	require.False(t, published.LockedUntil.Valid)
+}
+
+// 失敗太多次的 event 不再發布, 不會擋住後面的 event
+func TestDeadOutboxEvent(t *testing.T) {
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		AggregateType: "test",
+		AggregateID:   2,
+		EventType:     "test.created",
+		Payload:       json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	arg := MarkOutboxEventFailedParams{ID: event.ID, LastError: "400 Bad Request", MaxAttempts: 2}
+	failed, err := testQueries.MarkOutboxEventFailed(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, failed.FailedAt.Valid)
+
+	failed, err = testQueries.MarkOutboxEventFailed(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), failed.Attempts)
+	require.True(t, failed.FailedAt.Valid)
+
+	// 租約已過期, 但 dead event 不會被 claim
+	claimed, err := testQueries.ClaimOutboxEvents(context.Background(), ClaimOutboxEventsParams{
+		LockedUntil: time.Now().Add(-time.Minute),
+		BatchSize:   1 << 30,
+	})
+	require.NoError(t, err)
+	for _, e := range claimed {
+		require.NotEqual(t, event.ID, e.ID)
+	}
+}
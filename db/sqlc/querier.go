@@ -4,22 +4,99 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddAccountAccruedInterest(ctx context.Context, arg AddAccountAccruedInterestParams) (Accounts, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Accounts, error)
+	AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Accounts, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Sessions, error)
+	CaptureHold(ctx context.Context, arg CaptureHoldParams) (Holds, error)
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvents, error)
+	CountAccounts(ctx context.Context) (int64, error)
+	CountTransfers(ctx context.Context) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error)
+	CreateBalanceSnapshot(ctx context.Context, arg CreateBalanceSnapshotParams) (BalanceSnapshots, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entries, error)
+	CreateFeeRule(ctx context.Context, arg CreateFeeRuleParams) (FeeRules, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Holds, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKeys, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccruals, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvents, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfers, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRuns, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Sessions, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountTransferLimit(ctx context.Context, accountID int64) error
+	DeleteFeeRule(ctx context.Context, id int64) error
+	ExpireHolds(ctx context.Context) ([]Holds, error)
 	GetAccount(ctx context.Context, id int64) (Accounts, error)
+	GetAccountBalanceBefore(ctx context.Context, arg GetAccountBalanceBeforeParams) (int64, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Accounts, error)
+	GetAccountTransferLimit(ctx context.Context, accountID int64) (AccountTransferLimits, error)
+	GetCashAccount(ctx context.Context, currency string) (Accounts, error)
+	GetDailyOutgoingTransfers(ctx context.Context, arg GetDailyOutgoingTransfersParams) (GetDailyOutgoingTransfersRow, error)
+	GetDueScheduledTransferForUpdate(ctx context.Context, now time.Time) (ScheduledTransfers, error)
 	GetEntry(ctx context.Context, id int64) (Entries, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRates, error)
+	GetFeeRevenueAccount(ctx context.Context, currency string) (Accounts, error)
+	GetFeeRule(ctx context.Context, arg GetFeeRuleParams) (FeeRules, error)
+	GetHold(ctx context.Context, id int64) (Holds, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Holds, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKeys, error)
+	GetInterestExpenseAccount(ctx context.Context, currency string) (Accounts, error)
+	GetInterestRate(ctx context.Context, accountType string) (InterestRates, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfers, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfers, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Sessions, error)
 	GetTransfer(ctx context.Context, id int64) (Transfers, error)
+	GetTransferLimit(ctx context.Context, accountType string) (TransferLimits, error)
+	GetUser(ctx context.Context, username string) (Users, error)
+	ListAccountCurrencies(ctx context.Context, ids []int64) ([]ListAccountCurrenciesRow, error)
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]Entries, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfers, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Accounts, error)
+	ListAccountsPage(ctx context.Context, arg ListAccountsPageParams) ([]Accounts, error)
+	ListAccountsToAccrueInterest(ctx context.Context, accrualDate time.Time) ([]int64, error)
+	ListAccountsToPayInterest(ctx context.Context) ([]int64, error)
+	ListAccountsToSnapshot(ctx context.Context, snapshotAt time.Time) ([]int64, error)
+	ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error)
+	ListBalanceSnapshots(ctx context.Context, arg ListBalanceSnapshotsParams) ([]BalanceSnapshots, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entries, error)
+	ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entries, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRates, error)
+	ListFeeRules(ctx context.Context) ([]FeeRules, error)
+	ListIncomingTransfers(ctx context.Context, arg ListIncomingTransfersParams) ([]Transfers, error)
+	ListInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccruals, error)
+	ListInterestRates(ctx context.Context) ([]InterestRates, error)
+	ListOrphanEntries(ctx context.Context) ([]ListOrphanEntriesRow, error)
+	ListOutboxEventsByAggregate(ctx context.Context, arg ListOutboxEventsByAggregateParams) ([]OutboxEvents, error)
+	ListOutgoingTransfers(ctx context.Context, arg ListOutgoingTransfersParams) ([]Transfers, error)
+	ListScheduledTransferRunsPage(ctx context.Context, arg ListScheduledTransferRunsPageParams) ([]ScheduledTransferRuns, error)
+	ListScheduledTransfersPage(ctx context.Context, arg ListScheduledTransfersPageParams) ([]ScheduledTransfers, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfers, error)
+	ListTransfersBetween(ctx context.Context, arg ListTransfersBetweenParams) ([]Transfers, error)
+	ListUnmatchedTransfers(ctx context.Context) ([]ListUnmatchedTransfersRow, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) (OutboxEvents, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) (OutboxEvents, error)
+	PayInterestAccruals(ctx context.Context, arg PayInterestAccrualsParams) (int64, error)
+	ReleaseOutboxEvents(ctx context.Context, ids []int64) error
+	SetAccountOverdraftLimit(ctx context.Context, arg SetAccountOverdraftLimitParams) (Accounts, error)
+	SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Accounts, error)
+	SetHoldStatus(ctx context.Context, arg SetHoldStatusParams) (Holds, error)
+	SetScheduledTransferNextRun(ctx context.Context, arg SetScheduledTransferNextRunParams) (ScheduledTransfers, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Accounts, error)
+	UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Accounts, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfers, error)
+	UpsertAccountTransferLimit(ctx context.Context, arg UpsertAccountTransferLimitParams) (AccountTransferLimits, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRates, error)
+	UpsertInterestRate(ctx context.Context, arg UpsertInterestRateParams) (InterestRates, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimits, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// balance of account is updated by AddAccountBalance, and the entries are created separately,
+// UpdateAccount can change the balance without entry, so they may drift apart.
+// Reconcile checks the ledger and reports the records which are not consistent.
+
+// ReconcileReport is the result of Reconcile
+type ReconcileReport struct {
+	CheckedAt          time.Time                   `json:"checked_at"`
+	Accounts           int64                       `json:"accounts"`
+	Transfers          int64                       `json:"transfers"`
+	BalanceMismatches  []ListBalanceMismatchesRow  `json:"balance_mismatches"`
+	UnmatchedTransfers []ListUnmatchedTransfersRow `json:"unmatched_transfers"`
+	OrphanEntries      []ListOrphanEntriesRow      `json:"orphan_entries"`
+}
+
+// OK returns true if no problem is found
+func (report ReconcileReport) OK() bool {
+	return len(report.BalanceMismatches) == 0 && len(report.UnmatchedTransfers) == 0 && len(report.OrphanEntries) == 0
+}
+
+// Reconcile compares the balance of every account with the sum of its entries,
+// checks every transfer has exactly one entry of each account,
+// and every entry belongs to a transfer.
+// all queries run in one read-only repeatable read transaction,
+// so the report is a consistent snapshot even if transfers are made at the same time.
+func Reconcile(ctx context.Context, store Store) (ReconcileReport, error) {
+	var report ReconcileReport
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.ExecTx(ctx, opts, func(q Querier) error {
+		var err error
+
+		report.CheckedAt = time.Now().UTC()
+		report.Accounts, err = q.CountAccounts(ctx)
+		if err != nil {
+			return err
+		}
+		report.Transfers, err = q.CountTransfers(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.BalanceMismatches, err = q.ListBalanceMismatches(ctx)
+		if err != nil {
+			return err
+		}
+		report.UnmatchedTransfers, err = q.ListUnmatchedTransfers(ctx)
+		if err != nil {
+			return err
+		}
+		report.OrphanEntries, err = q.ListOrphanEntries(ctx)
+		return err
+	})
+	if err != nil {
+		return ReconcileReport{}, err
+	}
+
+	// empty list instead of null in the JSON report
+	if report.BalanceMismatches == nil {
+		report.BalanceMismatches = []ListBalanceMismatchesRow{}
+	}
+	if report.UnmatchedTransfers == nil {
+		report.UnmatchedTransfers = []ListUnmatchedTransfersRow{}
+	}
+	if report.OrphanEntries == nil {
+		report.OrphanEntries = []ListOrphanEntriesRow{}
+	}
+	return report, nil
+}
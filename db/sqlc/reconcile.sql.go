@@ -0,0 +1,195 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: reconcile.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const countAccounts = `-- name: CountAccounts :one
+SELECT count(*) FROM accounts
+`
+
+func (q *Queries) CountAccounts(ctx context.Context) (int64, error) {
+	row := q.queryRow(ctx, q.countAccountsStmt, countAccounts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTransfers = `-- name: CountTransfers :one
+SELECT count(*) FROM transfers
+`
+
+func (q *Queries) CountTransfers(ctx context.Context) (int64, error) {
+	row := q.queryRow(ctx, q.countTransfersStmt, countTransfers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const listBalanceMismatches = `-- name: ListBalanceMismatches :many
+SELECT
+    a.id AS account_id,
+    a.balance,
+    COALESCE(sum(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(sum(e.amount), 0)
+ORDER BY a.id
+`
+
+type ListBalanceMismatchesRow struct {
+	AccountID      int64 `json:"accountID"`
+	Balance        int64 `json:"balance"`
+	EntriesBalance int64 `json:"entriesBalance"`
+}
+
+// the balance of an account should always be the sum of its entries
+func (q *Queries) ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error) {
+	rows, err := q.query(ctx, q.listBalanceMismatchesStmt, listBalanceMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBalanceMismatchesRow
+	for rows.Next() {
+		var i ListBalanceMismatchesRow
+		if err := rows.Scan(&i.AccountID, &i.Balance, &i.EntriesBalance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanEntries = `-- name: ListOrphanEntries :many
+SELECT
+    e.id AS entry_id,
+    e.account_id,
+    e.amount,
+    e.transfer_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE
+    t.id IS NULL OR
+    NOT (
+        (e.account_id = t.from_account_id AND e.amount = -t.amount) OR
+        (e.account_id = t.to_account_id AND e.amount = t.to_amount)
+    ) OR
+    EXISTS (
+        SELECT 1 FROM entries x
+        WHERE x.transfer_id = e.transfer_id AND x.account_id = e.account_id AND x.id < e.id
+    )
+ORDER BY e.id
+`
+
+type ListOrphanEntriesRow struct {
+	EntryID    int64         `json:"entryID"`
+	AccountID  int64         `json:"accountID"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transferID"`
+}
+
+// every entry should be one of the two entries of its transfer:
+// the from account with the negative amount, or the to account with the to_amount.
+// the entries without transfer, with the wrong account or amount, and the extra entries of a transfer are reported.
+func (q *Queries) ListOrphanEntries(ctx context.Context) ([]ListOrphanEntriesRow, error) {
+	rows, err := q.query(ctx, q.listOrphanEntriesStmt, listOrphanEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrphanEntriesRow
+	for rows.Next() {
+		var i ListOrphanEntriesRow
+		if err := rows.Scan(
+			&i.EntryID,
+			&i.AccountID,
+			&i.Amount,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnmatchedTransfers = `-- name: ListUnmatchedTransfers :many
+SELECT
+    t.id AS transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    count(DISTINCT f.id) AS from_entries,
+    count(DISTINCT d.id) AS to_entries
+FROM transfers t
+LEFT JOIN entries f ON
+    f.transfer_id = t.id AND
+    f.account_id = t.from_account_id AND
+    f.amount = -t.amount
+LEFT JOIN entries d ON
+    d.transfer_id = t.id AND
+    d.account_id = t.to_account_id AND
+    d.amount = t.to_amount
+GROUP BY t.id
+HAVING count(DISTINCT f.id) <> 1 OR count(DISTINCT d.id) <> 1
+ORDER BY t.id
+`
+
+type ListUnmatchedTransfersRow struct {
+	TransferID    int64 `json:"transferID"`
+	FromAccountID int64 `json:"fromAccountID"`
+	ToAccountID   int64 `json:"toAccountID"`
+	Amount        int64 `json:"amount"`
+	FromEntries   int64 `json:"fromEntries"`
+	ToEntries     int64 `json:"toEntries"`
+}
+
+// the entries refer to the transfer which created them,
+// a transfer should have exactly one entry of each account with the amount of the transfer.
+func (q *Queries) ListUnmatchedTransfers(ctx context.Context) ([]ListUnmatchedTransfersRow, error) {
+	rows, err := q.query(ctx, q.listUnmatchedTransfersStmt, listUnmatchedTransfers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnmatchedTransfersRow
+	for rows.Next() {
+		var i ListUnmatchedTransfersRow
+		if err := rows.Scan(
+			&i.TransferID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.FromEntries,
+			&i.ToEntries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	store := NewStore(testDB)
+
+	// 沒有 entry 的帳戶, balance 和 entries 不一致
+	drifted := createRandomAccount(t)
+	require.NotZero(t, drifted.Balance)
+
+	// 用 TransferTx 轉帳, transfer 有一對 entries
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// 直接建立 transfer, 沒有 entries
+	unmatched := createRandomTransfer(t, account1, account2, 10)
+
+	// 沒有 transfer 的 entry, 和 transfer 多出來的 entry
+	orphan := createRandomEntry(t, account1, 10)
+	duplicated, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	extra, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID:  account2.ID,
+		Amount:     10,
+		TransferID: sql.NullInt64{Int64: duplicated.Transfer.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	report, err := Reconcile(context.Background(), store)
+	require.NoError(t, err)
+	require.False(t, report.OK())
+	require.NotZero(t, report.Accounts)
+	require.NotZero(t, report.Transfers)
+	require.NotZero(t, report.CheckedAt)
+
+	mismatches := map[int64]ListBalanceMismatchesRow{}
+	for _, row := range report.BalanceMismatches {
+		mismatches[row.AccountID] = row
+	}
+	require.Contains(t, mismatches, drifted.ID)
+	require.Equal(t, drifted.Balance, mismatches[drifted.ID].Balance)
+	require.Zero(t, mismatches[drifted.ID].EntriesBalance)
+
+	unmatchedTransfers := map[int64]ListUnmatchedTransfersRow{}
+	for _, row := range report.UnmatchedTransfers {
+		unmatchedTransfers[row.TransferID] = row
+	}
+	require.Contains(t, unmatchedTransfers, unmatched.ID)
+	require.Zero(t, unmatchedTransfers[unmatched.ID].FromEntries)
+	require.Zero(t, unmatchedTransfers[unmatched.ID].ToEntries)
+	require.NotContains(t, unmatchedTransfers, result.Transfer.ID)
+
+	orphanEntries := map[int64]ListOrphanEntriesRow{}
+	for _, row := range report.OrphanEntries {
+		orphanEntries[row.EntryID] = row
+	}
+	require.Contains(t, orphanEntries, orphan.ID)
+	require.False(t, orphanEntries[orphan.ID].TransferID.Valid)
+	require.Contains(t, orphanEntries, extra.ID)
+	require.Equal(t, duplicated.Transfer.ID, orphanEntries[extra.ID].TransferID.Int64)
+	// transfer 原本的兩個 entries 不是 orphan
+	require.NotContains(t, orphanEntries, result.FromEntry.ID)
+	require.NotContains(t, orphanEntries, result.ToEntry.ID)
+	require.NotContains(t, orphanEntries, duplicated.ToEntry.ID)
+	require.Equal(t, int64(2), unmatchedTransfers[duplicated.Transfer.ID].ToEntries)
+}
+
+// 同一個 transaction 的 transfers 有相同的 created_at, entries 用 transfer_id 對應, 不會對到別的 transfer
+func TestReconcileSameTransaction(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	var result TransferTxResult
+	var unmatched Transfers
+	err := store.execTx(context.Background(), nil, func(q *Queries) error {
+		var err error
+		result, err = transfer(context.Background(), q, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		if err != nil {
+			return err
+		}
+		// 相同帳戶和金額的 transfer, 沒有 entries
+		unmatched, err = q.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID:   account1.ID,
+			ToAccountID:     account2.ID,
+			Amount:          10,
+			ToAmount:        10,
+			ExchangeRate:    "1",
+			RoundingResidue: "0",
+		})
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.CreatedAt, unmatched.CreatedAt)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, result.Transfer.ID, result.ToEntry.TransferID.Int64)
+
+	report, err := Reconcile(context.Background(), store)
+	require.NoError(t, err)
+
+	unmatchedTransfers := map[int64]ListUnmatchedTransfersRow{}
+	for _, row := range report.UnmatchedTransfers {
+		unmatchedTransfers[row.TransferID] = row
+	}
+	require.Contains(t, unmatchedTransfers, unmatched.ID)
+	require.Zero(t, unmatchedTransfers[unmatched.ID].FromEntries)
+	require.Zero(t, unmatchedTransfers[unmatched.ID].ToEntries)
+	require.NotContains(t, unmatchedTransfers, result.Transfer.ID)
+}
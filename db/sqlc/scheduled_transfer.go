@@ -0,0 +1,99 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// status of a scheduled transfer, see the migration 000011_add_scheduled_transfers
+const (
+	ScheduledTransferStatusActive    = "active"
+	ScheduledTransferStatusPaused    = "paused"
+	ScheduledTransferStatusCancelled = "cancelled"
+)
+
+// status of a run of scheduled transfer
+const (
+	ScheduledTransferRunSucceeded = "succeeded"
+	ScheduledTransferRunFailed    = "failed"
+)
+
+// ErrInvalidSchedule is returned if the recurrence of a scheduled transfer is not valid
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ParseSchedule checks the recurrence of a scheduled transfer,
+// exactly one of the standard cron expression and the interval must be set.
+// the cron expression is evaluated in the location of the time given to Next, it is always UTC here.
+func ParseSchedule(cronExpression string, intervalSeconds int64) (cron.Schedule, error) {
+	if (cronExpression == "") == (intervalSeconds <= 0) {
+		return nil, fmt.Errorf("%w: either cron expression or interval must be set", ErrInvalidSchedule)
+	}
+	if intervalSeconds > math.MaxInt64/int64(time.Second) {
+		return nil, fmt.Errorf("%w: interval %d seconds is too long", ErrInvalidSchedule, intervalSeconds)
+	}
+	if intervalSeconds > 0 {
+		return cron.ConstantDelaySchedule{Delay: time.Duration(intervalSeconds) * time.Second}, nil
+	}
+
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	return schedule, nil
+}
+
+// FirstScheduledRun returns the time of the first run which is not before start
+// the interval starts at start, the cron expression runs at its first time from start.
+func FirstScheduledRun(cronExpression string, intervalSeconds int64, start time.Time) (time.Time, error) {
+	start = start.UTC().Truncate(time.Second)
+	if intervalSeconds > 0 {
+		if _, err := ParseSchedule(cronExpression, intervalSeconds); err != nil {
+			return time.Time{}, err
+		}
+		return start, nil
+	}
+
+	schedule, err := ParseSchedule(cronExpression, intervalSeconds)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Next returns the time strictly after its input, start itself is included by going back a bit
+	// the zero time means the cron expression never fires, e.g. "0 0 30 2 *"
+	first := schedule.Next(start.Add(-time.Nanosecond))
+	if first.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: %q never runs", ErrInvalidSchedule, cronExpression)
+	}
+	return first, nil
+}
+
+// NextScheduledRun returns the time of the next run after now
+// the runs missed while the scheduler was not running are skipped, they are not made up.
+// the interval is counted from the last scheduled time, so it doesn't drift by the delay of the scheduler.
+// an error is returned if there is no next run after now, the schedule would be due forever otherwise.
+func NextScheduledRun(scheduled ScheduledTransfers, now time.Time) (time.Time, error) {
+	schedule, err := ParseSchedule(scheduled.CronExpression, scheduled.IntervalSeconds)
+	if err != nil {
+		return time.Time{}, err
+	}
+	now = now.UTC()
+
+	var next time.Time
+	if scheduled.IntervalSeconds > 0 {
+		interval := time.Duration(scheduled.IntervalSeconds) * time.Second
+		next = scheduled.NextRunAt.UTC().Add(interval)
+		if !next.After(now) {
+			next = next.Add((now.Sub(next)/interval + 1) * interval)
+		}
+	} else {
+		next = schedule.Next(now)
+	}
+
+	if !next.After(now) {
+		return time.Time{}, fmt.Errorf("%w: no run after %s", ErrInvalidSchedule, now.Format(time.RFC3339))
+	}
+	return next, nil
+}
@@ -0,0 +1,370 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: scheduled_transfer.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createScheduledTransfer = `-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    cron_expression,
+    interval_seconds,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at
+`
+
+type CreateScheduledTransferParams struct {
+	Owner           string    `json:"owner"`
+	FromAccountID   int64     `json:"fromAccountID"`
+	ToAccountID     int64     `json:"toAccountID"`
+	Amount          int64     `json:"amount"`
+	CronExpression  string    `json:"cronExpression"`
+	IntervalSeconds int64     `json:"intervalSeconds"`
+	NextRunAt       time.Time `json:"nextRunAt"`
+}
+
+func (q *Queries) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.createScheduledTransferStmt, createScheduledTransfer,
+		arg.Owner,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.CronExpression,
+		arg.IntervalSeconds,
+		arg.NextRunAt,
+	)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createScheduledTransferRun = `-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    scheduled_at,
+    status,
+    transfer_id,
+    error
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, scheduled_transfer_id, scheduled_at, status, transfer_id, error, created_at
+`
+
+type CreateScheduledTransferRunParams struct {
+	ScheduledTransferID int64         `json:"scheduledTransferID"`
+	ScheduledAt         time.Time     `json:"scheduledAt"`
+	Status              string        `json:"status"`
+	TransferID          sql.NullInt64 `json:"transferID"`
+	Error               string        `json:"error"`
+}
+
+func (q *Queries) CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRuns, error) {
+	row := q.queryRow(ctx, q.createScheduledTransferRunStmt, createScheduledTransferRun,
+		arg.ScheduledTransferID,
+		arg.ScheduledAt,
+		arg.Status,
+		arg.TransferID,
+		arg.Error,
+	)
+	var i ScheduledTransferRuns
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduledTransferID,
+		&i.ScheduledAt,
+		&i.Status,
+		&i.TransferID,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDueScheduledTransferForUpdate = `-- name: GetDueScheduledTransferForUpdate :one
+SELECT id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= $1::timestamptz
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// the due schedule is locked until the end of the transaction,
+// SKIP LOCKED lets the other schedulers pick the next one instead of waiting,
+// so more than one server can run the scheduler at the same time.
+func (q *Queries) GetDueScheduledTransferForUpdate(ctx context.Context, now time.Time) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.getDueScheduledTransferForUpdateStmt, getDueScheduledTransferForUpdate, now)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransfer = `-- name: GetScheduledTransfer :one
+SELECT id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.getScheduledTransferStmt, getScheduledTransfer, id)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransferForUpdate = `-- name: GetScheduledTransferForUpdate :one
+SELECT id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+// the schedule is locked, so it is not changed by the owner and the scheduler at the same time
+func (q *Queries) GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.getScheduledTransferForUpdateStmt, getScheduledTransferForUpdate, id)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listScheduledTransferRunsPage = `-- name: ListScheduledTransferRunsPage :many
+SELECT id, scheduled_transfer_id, scheduled_at, status, transfer_id, error, created_at FROM scheduled_transfer_runs
+WHERE
+    scheduled_transfer_id = $1 AND
+    (created_at, id) > ($2::timestamptz, $3::bigint)
+ORDER BY created_at, id
+LIMIT $5
+OFFSET $4
+`
+
+type ListScheduledTransferRunsPageParams struct {
+	ScheduledTransferID int64     `json:"scheduledTransferID"`
+	AfterCreatedAt      time.Time `json:"afterCreatedAt"`
+	AfterID             int64     `json:"afterID"`
+	PageOffset          int32     `json:"pageOffset"`
+	PageLimit           int32     `json:"pageLimit"`
+}
+
+func (q *Queries) ListScheduledTransferRunsPage(ctx context.Context, arg ListScheduledTransferRunsPageParams) ([]ScheduledTransferRuns, error) {
+	rows, err := q.query(ctx, q.listScheduledTransferRunsPageStmt, listScheduledTransferRunsPage,
+		arg.ScheduledTransferID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledTransferRuns
+	for rows.Next() {
+		var i ScheduledTransferRuns
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScheduledTransferID,
+			&i.ScheduledAt,
+			&i.Status,
+			&i.TransferID,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledTransfersPage = `-- name: ListScheduledTransfersPage :many
+SELECT id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE
+    owner = $1 AND
+    (created_at, id) > ($2::timestamptz, $3::bigint)
+ORDER BY created_at, id
+LIMIT $5
+OFFSET $4
+`
+
+type ListScheduledTransfersPageParams struct {
+	Owner          string    `json:"owner"`
+	AfterCreatedAt time.Time `json:"afterCreatedAt"`
+	AfterID        int64     `json:"afterID"`
+	PageOffset     int32     `json:"pageOffset"`
+	PageLimit      int32     `json:"pageLimit"`
+}
+
+// keyset pagination like ListAccountsPage
+func (q *Queries) ListScheduledTransfersPage(ctx context.Context, arg ListScheduledTransfersPageParams) ([]ScheduledTransfers, error) {
+	rows, err := q.query(ctx, q.listScheduledTransfersPageStmt, listScheduledTransfersPage,
+		arg.Owner,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledTransfers
+	for rows.Next() {
+		var i ScheduledTransfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CronExpression,
+			&i.IntervalSeconds,
+			&i.Status,
+			&i.NextRunAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setScheduledTransferNextRun = `-- name: SetScheduledTransferNextRun :one
+UPDATE scheduled_transfers
+SET next_run_at = $1, updated_at = now()
+WHERE id = $2
+RETURNING id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at
+`
+
+type SetScheduledTransferNextRunParams struct {
+	NextRunAt time.Time `json:"nextRunAt"`
+	ID        int64     `json:"id"`
+}
+
+func (q *Queries) SetScheduledTransferNextRun(ctx context.Context, arg SetScheduledTransferNextRunParams) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.setScheduledTransferNextRunStmt, setScheduledTransferNextRun, arg.NextRunAt, arg.ID)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateScheduledTransfer = `-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+    amount = $1,
+    status = $2,
+    next_run_at = $3,
+    updated_at = now()
+WHERE id = $4
+RETURNING id, owner, from_account_id, to_account_id, amount, cron_expression, interval_seconds, status, next_run_at, created_at, updated_at
+`
+
+type UpdateScheduledTransferParams struct {
+	Amount    int64     `json:"amount"`
+	Status    string    `json:"status"`
+	NextRunAt time.Time `json:"nextRunAt"`
+	ID        int64     `json:"id"`
+}
+
+func (q *Queries) UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfers, error) {
+	row := q.queryRow(ctx, q.updateScheduledTransferStmt, updateScheduledTransfer,
+		arg.Amount,
+		arg.Status,
+		arg.NextRunAt,
+		arg.ID,
+	)
+	var i ScheduledTransfers
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CronExpression,
+		&i.IntervalSeconds,
+		&i.Status,
+		&i.NextRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
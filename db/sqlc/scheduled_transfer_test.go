@@ -0,0 +1,99 @@
+package db
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	_, err := ParseSchedule("0 9 1 * *", 0)
+	require.NoError(t, err)
+	_, err = ParseSchedule("@monthly", 0)
+	require.NoError(t, err)
+	_, err = ParseSchedule("", 3600)
+	require.NoError(t, err)
+
+	// 兩者都沒有, 或兩者都有
+	for _, tc := range []struct {
+		cronExpression  string
+		intervalSeconds int64
+	}{
+		{"", 0},
+		{"0 9 1 * *", 3600},
+		{"0 9 1 *", 0},
+		{"every day", 0},
+		{"", math.MaxInt64/int64(time.Second) + 1},
+	} {
+		_, err = ParseSchedule(tc.cronExpression, tc.intervalSeconds)
+		require.ErrorIs(t, err, ErrInvalidSchedule, tc.cronExpression)
+	}
+}
+
+func TestFirstScheduledRun(t *testing.T) {
+	start := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	// start 本身符合 cron 時, 第一次就是 start
+	first, err := FirstScheduledRun("0 9 1 * *", 0, start)
+	require.NoError(t, err)
+	require.Equal(t, start, first)
+
+	first, err = FirstScheduledRun("0 9 1 * *", 0, start.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 11, 1, 9, 0, 0, 0, time.UTC), first)
+
+	first, err = FirstScheduledRun("", 3600, start.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, start, first)
+
+	_, err = FirstScheduledRun("", 0, start)
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+
+	// 2 月 30 日永遠不會到
+	_, err = FirstScheduledRun("0 0 30 2 *", 0, start)
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+}
+
+func TestNextScheduledRun(t *testing.T) {
+	scheduled := ScheduledTransfers{
+		IntervalSeconds: 3600,
+		NextRunAt:       time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	// 間隔從上次預定的時間開始算, 不受 scheduler 延遲影響
+	next, err := NextScheduledRun(scheduled, scheduled.NextRunAt.Add(5*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC), next)
+
+	// 錯過的不補做
+	next, err = NextScheduledRun(scheduled, scheduled.NextRunAt.Add(150*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC), next)
+
+	next, err = NextScheduledRun(scheduled, scheduled.NextRunAt.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC), next)
+
+	scheduled.IntervalSeconds = 0
+	scheduled.CronExpression = "0 9 1 * *"
+	next, err = NextScheduledRun(scheduled, scheduled.NextRunAt.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 11, 1, 9, 0, 0, 0, time.UTC), next)
+
+	next, err = NextScheduledRun(scheduled, time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 4, 1, 9, 0, 0, 0, time.UTC), next)
+
+	// 沒有下一次, 回傳錯誤讓排程被取消, 不會一直到期
+	scheduled.CronExpression = "0 0 30 2 *"
+	_, err = NextScheduledRun(scheduled, scheduled.NextRunAt)
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+
+	// 跳過的時間太長, 計算 overflow 到過去的時間
+	scheduled.CronExpression = ""
+	scheduled.IntervalSeconds = 3600
+	_, err = NextScheduledRun(scheduled, scheduled.NextRunAt.AddDate(300, 0, 0))
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+}
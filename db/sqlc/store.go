@@ -4,7 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"sort"
+	"time"
 )
 
 // mock DB to test HTTP API, need to create an interface
@@ -14,6 +15,16 @@ import (
 type Store interface{
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) 
+	DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (Holds, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, holdID int64) (Holds, error)
+	ExpireHoldsTx(ctx context.Context) ([]Holds, error)
+	RunScheduledTransferTx(ctx context.Context, now time.Time) (RunScheduledTransferTxResult, error)
+	AccrueInterestTx(ctx context.Context, accountID int64, date time.Time) (InterestAccruals, error)
+	PayInterestTx(ctx context.Context, accountID int64) (TransferTxResult, error)
+	ExecTx(ctx context.Context, opts *sql.TxOptions, fn func(Querier) error) error
 }
 
 // Store struct provides all functions to execute db queries and transactions
@@ -22,6 +33,8 @@ type Store interface{
 type SQLStore struct {
 	*Queries
 	db *sql.DB
+	// fees decides the fee of TransferTx, nil means no fee
+	fees FeeSchedule
 }
 
 
@@ -38,21 +51,88 @@ type SQLStore struct {
 //it should return the real DB implementation of the interface, 
 //which is SQLStore.
 func NewStore(db *sql.DB) Store {
+	return NewStoreWithFees(db, DBFeeSchedule{})
+}
+
+// NewStoreWithFees creates a new Store which charges the fees of transfers by the fee schedule,
+// NewStore uses the rules in the fee_rules table.
+func NewStoreWithFees(db *sql.DB, fees FeeSchedule) Store {
 	return &SQLStore{
-		db: db,
+		db:      db,
 		Queries: New(db),
+		fees:    fees,
 	}
 }
 
 
+// postgres may abort a transaction because of deadlock or serialization failure,
+// such transaction can be safely retried from the beginning.
+// the wait time between retries is doubled each time: 10ms, 20ms, 40ms
+const (
+	maxTxRetries    = 3
+	txRetryBaseWait = 10 * time.Millisecond
+)
+
+// ExecTx executes fn within a database transaction with the given options
+// opts can set the isolation level or read-only mode of the transaction, nil means default options.
+// it is exported so handlers can compose their own atomic operations with any queries.
+// fn may be called more than once if the transaction is retried.
+func (store *SQLStore) ExecTx(ctx context.Context, opts *sql.TxOptions, fn func(Querier) error) error {
+	err := store.execTx(ctx, opts, func(q *Queries) error {
+		return fn(q)
+	})
+	return TranslateError(err)
+}
+
 // execTx executes a function within a database transaction
+// if the transaction fails with a retryable error, it will be retried at most maxTxRetries times.
+// so fn must be safe to be called more than once.
+func (store *SQLStore) execTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = store.runTx(ctx, opts, fn)
+		if err == nil || !isRetryableTxError(err) || attempt >= maxTxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(txRetryBaseWait << attempt):
+		}
+	}
+}
+
+// isRetryableTxError reports whether the transaction is aborted by postgres
+// with serialization_failure (40001) or deadlock_detected (40P01)
+func isRetryableTxError(err error) bool {
+	code := ErrorCode(err)
+	return code == "40001" || code == "40P01"
+}
+
+// runTx runs fn within 1 single database transaction
+// the error returned by fn is returned as it is, so the caller can still check it,
+// errors of begin and commit are wrapped with more context.
 //----- after mock DB------ Store change to SQLStore
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+func (store *SQLStore) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) (err error) {
 	// Call store.db.BiginTx() for start a new db transaction
-	tx, err := store.db.BeginTx(ctx, nil)
+	// it used to be log.Fatal here, which stopped the whole server when db was temporarily unavailable
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("cannot begin transaction: %w", err)
 	}
+	// if fn panics, the transaction must be rolled back,
+	// otherwise the connection is held by the unfinished transaction.
+	// the panic is turned into an error, so the caller (and the server) can keep going.
+	defer func() {
+		if p := recover(); p != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("panic in transaction: %v, rb err: %v", p, rbErr)
+				return
+			}
+			err = fmt.Errorf("panic in transaction: %v", p)
+		}
+	}()
 	// call New() with created transaction, and get back a new Queries object
 
 	q := New(tx)
@@ -61,29 +141,53 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
 		}
 		return err
 	}
 	// all operation successful, commit the transaction
-	return tx.Commit()
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit transaction: %w", err)
+	}
+	return nil
 }
 
 // TransferTxParams contains the input parameters of the transfer transaction
 type TransferTxParams struct {
-	FromAccountID int64 `json: "from_account_id"`
-	ToAccountID   int64 `json: "to_account_id"`
-	Amount        int64 `json: "amount"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+	// ExchangeRate is required if the accounts have different currencies,
+	// Amount is debited in the currency of the from account,
+	// and Amount * ExchangeRate, rounded down, is credited in the currency of the to account.
+	// it is ignored if both accounts have the same currency.
+	ExchangeRate string `json:"exchange_rate,omitempty"`
+	// AfterTransfer is optional, it is called within the same transaction after the transfer is done,
+	// if it returns an error, the whole transfer is rolled back.
+	// e.g. the api saves the idempotency key of the request here
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
 }
 
 // TransferTxResult is the result of the transfer transaction
 type TransferTxResult struct {
-	Transfer    Transfers `json: "transfer"`
-	FromAccount Accounts  `json: "from_account"`
-	ToAccount   Accounts  `json: "to_account"`
-	FromEntry   Entries   `json: "from_entry"`
-	ToEntry     Entries   `json: "to_entry"`
+	Transfer    Transfers `json:"transfer"`
+	FromAccount Accounts  `json:"from_account"`
+	ToAccount   Accounts  `json:"to_account"`
+	FromEntry   Entries   `json:"from_entry"`
+	ToEntry     Entries   `json:"to_entry"`
+	// Fee is nil if no fee is charged, FromAccount is the balance after the fee
+	Fee *TransferFee `json:"fee,omitempty"`
 }
+
+// TransferFee is the fee of a transfer, it is a transfer from the from account to the fee revenue account
+type TransferFee struct {
+	Amount    int64     `json:"amount"`
+	Transfer  Transfers `json:"transfer"`
+	FromEntry Entries   `json:"from_entry"`
+	ToEntry   Entries   `json:"to_entry"`
+}
+
+// txKey is the context key of the transaction name, the tests use it to tell the concurrent transactions apart
 var txKey = struct{}{}
 
 // TransferTx performs a money transfer from one account to the other
@@ -94,97 +198,191 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	// empty TransferTxresult
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, nil, func(q *Queries) error {
 		var err error
 		// we can use the Queries object to call any individual CRUD function that it provides.
 		// the Queries object is created from 1 single database transaction
 		// so all of its provided methods that we call will be run within that transaction
+		result, err = userTransfer(ctx, q, store.fees, arg)
+		return err
+	})
+	// constraint violation, e.g. balance becomes lower than the overdraft limit, is returned as typed error
+	return result, TranslateError(err)
 
-		// 把編號後的tx帶入
-		txName := ctx.Value(txKey)
-		fmt.Println(txName, "create transfer")
-        
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
+}
+
+// transfer moves the money within the transaction of q
+// it is shared by TransferTx, DepositTx and WithdrawTx.
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams) (result TransferTxResult, err error) {
+	// frozen or closed account can not send or receive money
+	accounts, err := lockActiveAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	// the amount credited to the to account, it is converted if the currencies are different
+	transferArg := CreateTransferParams{
+		FromAccountID:   arg.FromAccountID,
+		ToAccountID:     arg.ToAccountID,
+		Amount:          arg.Amount,
+		ToAmount:        arg.Amount,
+		ExchangeRate:    "1",
+		RoundingResidue: "0",
+	}
+	fromCurrency, toCurrency := accounts[arg.FromAccountID].Currency, accounts[arg.ToAccountID].Currency
+	if fromCurrency != toCurrency {
+		if arg.ExchangeRate == "" {
+			return result, fmt.Errorf("%w: account [%d] %s vs account [%d] %s",
+				ErrCurrencyMismatch, arg.FromAccountID, fromCurrency, arg.ToAccountID, toCurrency)
 		}
-		//add account entries
-		fmt.Println(txName, "create entry1")
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			// because money is moving out of this account
-			Amount: -arg.Amount,
-		})
+		transferArg.ToAmount, transferArg.RoundingResidue, err = convertAmount(arg.Amount, arg.ExchangeRate)
 		if err != nil {
-			return err
+			return result, err
 		}
+		transferArg.ExchangeRate = arg.ExchangeRate
+	}
 
-		fmt.Println(txName, "create entry2")
+	result.Transfer, err = q.CreateTransfer(ctx, transferArg)
+	if err != nil {
+		return result, err
+	}
+	//add account entries
+	// the entries refer to the transfer, so the reconciliation can match them exactly
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		// because money is moving out of this account
+		Amount:     -arg.Amount,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		// because money is moving into this account
+		Amount:     transferArg.ToAmount,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+	// transfer record and 2 account entries are created
+	// get account -> update its balance
+	// ------ deadlock ------
+	// if tx1 transfers from account1 to account2, and tx2 transfers from account2 to account1 at the same time,
+	// tx1 locks account1 then waits for account2, tx2 locks account2 then waits for account1 -> deadlock.
+	// to avoid it, always update the account with smaller ID first,
+	// so all transactions acquire the locks in the same order.
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, transferArg.ToAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, transferArg.ToAmount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if arg.AfterTransfer != nil {
+		err = arg.AfterTransfer(q, result)
+	}
+	return result, err
+}
+
+// userTransfer is the transfer made by a user, it is shared by TransferTx and the scheduled transfers.
+// it moves the money like transfer, checks the transfer limits of the from account,
+// then charges the fee of the transfer within the same transaction.
+// AfterTransfer is called after the fee, so it gets the final result.
+func userTransfer(ctx context.Context, q *Queries, fees FeeSchedule, arg TransferTxParams) (result TransferTxResult, err error) {
+	afterTransfer := arg.AfterTransfer
+	arg.AfterTransfer = nil
+	result, err = transfer(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkTransferLimit(ctx, q, result.FromAccount, result.Transfer)
+	if err != nil {
+		return result, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			// because money is moving out of this account
-			Amount: arg.Amount,
-		})
+	if fees != nil {
+		currency := result.FromAccount.Currency
+		amount, err := fees.TransferFee(ctx, q, currency, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
 		}
-		// transfer record and 2 account entries are created
-		// TODO: update accounts' balance
-		// get account -> update its balance
-		fmt.Println(txName, "get account 1")
-		// account1, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
-		// if err != nil {
-		// 	return err
-		// }
-		// fmt.Println(txName, "update account 1")
-
-		// result.FromAccount, err = q.UpdateAccount(ctx, UpdateAccountParams{
-		// 	ID:      arg.FromAccountID,
-		// 	Balance: account1.Balance - arg.Amount,
-		// })
-		// if err != nil {
-		// 	return err
-		// }
-
-		result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-			ID:     arg.FromAccountID,
-			Amount: -arg.Amount,
-		})
-		if err != nil {
-			return err
+		if amount > 0 {
+			revenueAccount, err := q.GetFeeRevenueAccount(ctx, currency)
+			if err != nil {
+				return result, fmt.Errorf("cannot get fee revenue account of %s: %w", currency, err)
+			}
+			feeResult, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   revenueAccount.ID,
+				Amount:        amount,
+			})
+			if err != nil {
+				return result, err
+			}
+			result.FromAccount = feeResult.FromAccount
+			result.Fee = &TransferFee{
+				Amount:    amount,
+				Transfer:  feeResult.Transfer,
+				FromEntry: feeResult.FromEntry,
+				ToEntry:   feeResult.ToEntry,
+			}
 		}
+	}
+
+	if afterTransfer != nil {
+		err = afterTransfer(q, result)
+	}
+	return result, err
+}
+
+// lockActiveAccounts locks the accounts until the end of the transaction,
+// so their status can not be changed by others, and returns ErrAccountNotActive if one of them is frozen or closed.
+// the account with smaller ID is locked first, the same order as addMoney.
+// the locked accounts are returned by ID.
+func lockActiveAccounts(ctx context.Context, q *Queries, accountIDs ...int64) (map[int64]Accounts, error) {
+	sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
 
-		// fmt.Println(txName, "get account 2")
-
-		// account2, err := q.GetAccountForUpdate(ctx, arg.ToAccountID)
-		// if err != nil {
-		// 	return err
-		// }
-		// fmt.Println(txName, "update account ")
-
-		// result.ToAccount, err = q.UpdateAccount(ctx, UpdateAccountParams{
-		// 	ID:      arg.ToAccountID,
-		// 	Balance: account2.Balance + arg.Amount,
-		// })
-		// if err != nil {
-		// 	return err
-		// }
-		result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-			ID:     arg.ToAccountID,
-			Amount: arg.Amount,
-		})
+	accounts := make(map[int64]Accounts, len(accountIDs))
+	for _, id := range accountIDs {
+		account, err := q.GetAccountForUpdate(ctx, id)
 		if err != nil {
-			return err
+			return nil, TranslateNotFound(err, ErrAccountNotFound)
+		}
+		if account.Status != AccountStatusActive {
+			return nil, fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
 		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
 
-		return nil
+// addMoney adds amount1 to account1 and amount2 to account2, in this order.
+// the caller decides the order of 2 accounts, so the locks are always acquired in the same order
+func addMoney(
+	ctx context.Context,
+	q *Queries,
+	accountID1 int64,
+	amount1 int64,
+	accountID2 int64,
+	amount2 int64,
+) (account1 Accounts, account2 Accounts, err error) {
+	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     accountID1,
+		Amount: amount1,
 	})
+	if err != nil {
+		return
+	}
 
-	return result, err
-
+	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     accountID2,
+		Amount: amount2,
+	})
+	return
 }
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// CashTxParams contains the input parameters of the deposit and withdrawal transaction
+type CashTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// AfterTx is optional, it is called within the same transaction like TransferTxParams.AfterTransfer
+	AfterTx func(q Querier, result CashTxResult) error `json:"-"`
+}
+
+// CashTxResult is the result of the deposit and withdrawal transaction
+// the cash account belongs to the bank, so only the account of the user is returned.
+type CashTxResult struct {
+	Transfer Transfers `json:"transfer"`
+	Account  Accounts  `json:"account"`
+	Entry    Entries   `json:"entry"`
+}
+
+// DepositTx puts the money into the account
+// the money comes from the cash account of the same currency,
+// so the entries of the ledger are still balanced.
+func (store *SQLStore) DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, arg, true)
+}
+
+// WithdrawTx takes the money out of the account to the cash account of the same currency
+// ErrInsufficientFunds is returned if the balance is not enough.
+// a withdrawal is an outgoing transfer of the account, it is checked and counted by the transfer limits.
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, arg, false)
+}
+
+func (store *SQLStore) cashTx(ctx context.Context, arg CashTxParams, deposit bool) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return TranslateNotFound(err, ErrAccountNotFound)
+		}
+		if IsSystemAccount(account) {
+			return fmt.Errorf("%w: account [%d] is a system account", ErrAccountNotActive, account.ID)
+		}
+
+		cash, err := q.GetCashAccount(ctx, account.Currency)
+		if err != nil {
+			return fmt.Errorf("cannot get cash account of %s: %w", account.Currency, err)
+		}
+
+		transferArg := TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   cash.ID,
+			Amount:        arg.Amount,
+		}
+		if deposit {
+			transferArg.FromAccountID, transferArg.ToAccountID = cash.ID, account.ID
+		}
+
+		transferResult, err := transfer(ctx, q, transferArg)
+		if err != nil {
+			return err
+		}
+		if !deposit {
+			// the account is locked by transfer, so the withdrawals of the same account are counted one by one
+			err = checkTransferLimit(ctx, q, transferResult.FromAccount, transferResult.Transfer)
+			if err != nil {
+				return err
+			}
+		}
+
+		result = CashTxResult{
+			Transfer: transferResult.Transfer,
+			Account:  transferResult.FromAccount,
+			Entry:    transferResult.FromEntry,
+		}
+		if deposit {
+			result.Account = transferResult.ToAccount
+			result.Entry = transferResult.ToEntry
+		}
+
+		if arg.AfterTx != nil {
+			return arg.AfterTx(q, result)
+		}
+		return nil
+	})
+	return result, TranslateError(err)
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	cash, err := testQueries.GetCashAccount(context.Background(), account.Currency)
+	require.NoError(t, err)
+	require.Equal(t, CashAccountOwner, cash.Owner)
+
+	// 存款 deposit
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cash.ID, deposit.Transfer.FromAccountID)
+	require.Equal(t, account.ID, deposit.Transfer.ToAccountID)
+	require.Equal(t, account.ID, deposit.Entry.AccountID)
+	require.Equal(t, int64(100), deposit.Entry.Amount)
+	require.Equal(t, account.Balance+100, deposit.Account.Balance)
+
+	// 提款 withdrawal
+	withdrawal, err := store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, withdrawal.Transfer.FromAccountID)
+	require.Equal(t, cash.ID, withdrawal.Transfer.ToAccountID)
+	require.Equal(t, int64(-30), withdrawal.Entry.Amount)
+	require.Equal(t, account.Balance+70, withdrawal.Account.Balance)
+
+	// 現金帳戶的餘額反向變動 the cash account is changed in the opposite direction
+	updatedCash, err := testQueries.GetAccount(context.Background(), cash.ID)
+	require.NoError(t, err)
+	require.LessOrEqual(t, updatedCash.Balance, cash.Balance-70)
+
+	// 餘額不足 withdraw more than the balance
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    withdrawal.Account.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// 現金帳戶不能存提款 the cash account itself is rejected
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: cash.ID,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: 0,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxWithFee(t *testing.T) {
+	fees, err := NewStaticFeeSchedule([]FeeRules{{Currency: "USD", FlatFee: 30, Percentage: "0.01"}})
+	require.NoError(t, err)
+	store := NewStoreWithFees(testDB, fees)
+
+	account1 := createRandomAccountWithCurrency(t, "USD")
+	account2 := createRandomAccountWithCurrency(t, "USD")
+	revenue, err := testQueries.GetFeeRevenueAccount(context.Background(), "USD")
+	require.NoError(t, err)
+	require.True(t, IsSystemAccount(revenue))
+
+	// 10 * 0.01 + 30 = 30.1 -> 30
+	amount := int64(10)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Fee)
+	require.Equal(t, int64(30), result.Fee.Amount)
+
+	// 手續費是另一筆轉帳, 到手續費收入帳戶
+	require.Equal(t, account1.ID, result.Fee.Transfer.FromAccountID)
+	require.Equal(t, revenue.ID, result.Fee.Transfer.ToAccountID)
+	require.Equal(t, int64(30), result.Fee.Transfer.Amount)
+	require.Equal(t, int64(-30), result.Fee.FromEntry.Amount)
+	require.Equal(t, revenue.ID, result.Fee.ToEntry.AccountID)
+
+	// 轉出帳戶扣掉金額和手續費, 轉入帳戶只收到金額
+	require.Equal(t, account1.Balance-amount-30, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+amount, result.ToAccount.Balance)
+	updated, err := testQueries.GetFeeRevenueAccount(context.Background(), "USD")
+	require.NoError(t, err)
+	require.Equal(t, revenue.Balance+30, updated.Balance)
+
+	// AfterTransfer gets the result with the fee
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		AfterTransfer: func(q Querier, result TransferTxResult) error {
+			require.NotNil(t, result.Fee)
+			require.Equal(t, result.Fee.FromEntry.AccountID, result.FromAccount.ID)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	// no rule of the currency, no fee
+	account3 := createRandomAccountWithCurrency(t, "TWD")
+	account4 := createRandomAccountWithCurrency(t, "TWD")
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account3.ID,
+		ToAccountID:   account4.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Fee)
+	require.Equal(t, account3.Balance-amount, result.FromAccount.Balance)
+}
+
+func TestGetFeeRule(t *testing.T) {
+	// 用很大的 min_amount, 不影響其他測試的轉帳
+	base := int64(1) << 60
+	rule1, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency:   "TWD",
+		MinAmount:  base,
+		FlatFee:    30,
+		Percentage: "0",
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), rule1.ID)
+
+	rule2, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency:   "TWD",
+		MinAmount:  base + 1000,
+		Percentage: "0.001",
+		MaxFee:     500,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), rule2.ID)
+
+	fee, err := DBFeeSchedule{}.TransferFee(context.Background(), testQueries, "TWD", base+999)
+	require.NoError(t, err)
+	require.Equal(t, int64(30), fee)
+
+	fee, err = DBFeeSchedule{}.TransferFee(context.Background(), testQueries, "TWD", base+1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), fee)
+
+	// 同一個 currency 和 min_amount 只能有一條
+	_, err = testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency:   "TWD",
+		MinAmount:  base,
+		Percentage: "0",
+	})
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+}
+
+// 請款保留金額也是使用者的轉帳, 要收手續費
+func TestCaptureHoldTxWithFee(t *testing.T) {
+	fees, err := NewStaticFeeSchedule([]FeeRules{{Currency: "USD", FlatFee: 30, Percentage: "0"}})
+	require.NoError(t, err)
+	store := NewStoreWithFees(testDB, fees)
+
+	account1 := fundAccount(t, createRandomAccountWithCurrency(t, "USD"), 100)
+	account2 := createRandomAccountWithCurrency(t, "USD")
+	hold := placeRandomHold(t, store, account1, account2, 60, time.Now().Add(time.Hour))
+
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.NoError(t, err)
+	require.NotNil(t, result.Fee)
+	require.Equal(t, int64(30), result.Fee.Amount)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+
+	require.Equal(t, account1.Balance-60-30, result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, account2.Balance+60, result.ToAccount.Balance)
+
+	// 餘額不夠付手續費, 請款失敗, 保留金額不變
+	hold = placeRandomHold(t, store, result.FromAccount, account2, 10, time.Now().Add(time.Hour))
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), updated.Balance)
+	require.Equal(t, int64(10), updated.HeldAmount)
+}
@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// a hold reserves the money of an account for the to account, like the authorization of a card payment.
+// the balance is not changed, but the held amount is added to accounts.held_amount,
+// so the available balance is reduced, and the accounts_available_limit constraint
+// rejects any transfer which uses the reserved money.
+
+// PlaceHoldTxParams contains the input parameters of placing a hold
+type PlaceHoldTxParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// PlaceHoldTx reserves the amount of the account
+// ErrInsufficientFunds is returned if the available balance is not enough.
+func (store *SQLStore) PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (Holds, error) {
+	var hold Holds
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		accounts, err := lockActiveAccounts(ctx, q, arg.AccountID, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+		// the hold is captured without conversion
+		from, to := accounts[arg.AccountID], accounts[arg.ToAccountID]
+		if from.Currency != to.Currency {
+			return fmt.Errorf("%w: account [%d] %s vs account [%d] %s",
+				ErrCurrencyMismatch, from.ID, from.Currency, to.ID, to.Currency)
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount,
+			ExpiresAt:   arg.ExpiresAt,
+		})
+		return err
+	})
+	return hold, TranslateError(err)
+}
+
+// CaptureHoldTxParams contains the input parameters of capturing a hold
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount is optional, it can be less than the held amount, 0 means the whole held amount.
+	// the rest of the held amount is released.
+	Amount int64 `json:"amount"`
+	// AfterTransfer is passed to the transfer, like TransferTxParams.AfterTransfer
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
+}
+
+// CaptureHoldTxResult is the result of capturing a hold
+type CaptureHoldTxResult struct {
+	TransferTxResult
+	Hold Holds `json:"hold"`
+}
+
+// CaptureHoldTx turns the hold into a transfer from the account to the to account
+// the held amount is released and the transfer is made within the same transaction,
+// so the reserved money can be used by the transfer.
+// the capture is a transfer of the user, the transfer limits and the fee apply as TransferTx.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount < 0 || amount > hold.Amount {
+			return fmt.Errorf("%w: capture %d of hold [%d] with %d", ErrInvalidAmount, amount, hold.ID, hold.Amount)
+		}
+
+		// lock the accounts in the same order as transfer before changing held_amount
+		if _, err = lockActiveAccounts(ctx, q, hold.AccountID, hold.ToAccountID); err != nil {
+			return err
+		}
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = userTransfer(ctx, q, store.fees, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CaptureHold(ctx, CaptureHoldParams{
+			ID:         hold.ID,
+			TransferID: result.Transfer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(q, result.TransferTxResult)
+		}
+		return nil
+	})
+	return result, TranslateError(err)
+}
+
+// ReleaseHoldTx cancels the hold, the held amount is available again
+// a hold past expires_at which is not expired by ExpireHoldsTx yet can be released too,
+// so the owner doesn't have to wait for the next ExpireHoldsTx to use the money.
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, holdID int64) (Holds, error) {
+	var hold Holds
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		hold, err = lockHeldHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.SetHoldStatus(ctx, SetHoldStatusParams{
+			ID:     hold.ID,
+			Status: HoldStatusReleased,
+		})
+		return err
+	})
+	return hold, TranslateError(err)
+}
+
+// ExpireHoldsTx expires all the holds which are not captured before expires_at,
+// and releases their held amount. the expired holds are returned.
+// the holds are not expired automatically, so it should be run periodically,
+// the server process runs it every HOLD_EXPIRY_INTERVAL, see scheduler.HoldExpirer.
+func (store *SQLStore) ExpireHoldsTx(ctx context.Context) ([]Holds, error) {
+	var holds []Holds
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		holds, err = q.ExpireHolds(ctx)
+		if err != nil {
+			return err
+		}
+
+		held := map[int64]int64{}
+		var accountIDs []int64
+		for _, hold := range holds {
+			if _, ok := held[hold.AccountID]; !ok {
+				accountIDs = append(accountIDs, hold.AccountID)
+			}
+			held[hold.AccountID] += hold.Amount
+		}
+		// the account with smaller ID is updated first, the same order as transfer
+		sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+		for _, id := range accountIDs {
+			_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+				ID:     id,
+				Amount: -held[id],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return holds, TranslateError(err)
+}
+
+// lockActiveHold locks the hold until the end of the transaction,
+// and returns ErrHoldNotActive if it is already captured, released or expired.
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64) (Holds, error) {
+	hold, err := lockHeldHold(ctx, q, holdID)
+	if err != nil {
+		return hold, err
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return hold, fmt.Errorf("%w: hold [%d] is expired at %s", ErrHoldNotActive, hold.ID, hold.ExpiresAt)
+	}
+	return hold, nil
+}
+
+// lockHeldHold locks the hold until the end of the transaction,
+// and returns ErrHoldNotActive if it is already captured, released or expired by ExpireHoldsTx.
+// unlike lockActiveHold, the hold past expires_at is returned, its money is still held.
+func lockHeldHold(ctx context.Context, q *Queries, holdID int64) (Holds, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, TranslateNotFound(err, ErrHoldNotFound)
+	}
+	if hold.Status != HoldStatusHeld {
+		return hold, fmt.Errorf("%w: hold [%d] is %s", ErrHoldNotActive, hold.ID, hold.Status)
+	}
+	return hold, nil
+}
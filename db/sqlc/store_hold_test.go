@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func placeRandomHold(t *testing.T, store Store, account, toAccount Accounts, amount int64, expiresAt time.Time) Holds {
+	hold, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account.ID,
+		ToAccountID: toAccount.ID,
+		Amount:      amount,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, hold.ID)
+	require.Equal(t, account.ID, hold.AccountID)
+	require.Equal(t, toAccount.ID, hold.ToAccountID)
+	require.Equal(t, amount, hold.Amount)
+	require.Equal(t, HoldStatusHeld, hold.Status)
+	require.False(t, hold.TransferID.Valid)
+	return hold
+}
+
+func TestPlaceHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	placeRandomHold(t, store, account1, account2, 60, time.Now().Add(time.Hour))
+
+	// 餘額不變, 但可用餘額減少
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+	require.Equal(t, int64(60), updated.HeldAmount)
+	require.Equal(t, int64(40), AvailableBalance(updated))
+
+	// 可用餘額不足
+	_, err = store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      41,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// 轉帳也不能用被保留的錢
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	hold := placeRandomHold(t, store, account1, account2, 60, time.Now().Add(time.Hour))
+
+	// 只請款一部分, 剩下的保留金額也會釋放
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: 50,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.True(t, result.Hold.TransferID.Valid)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, int64(50), result.Transfer.Amount)
+
+	require.Equal(t, account1.Balance-50, result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, account2.Balance+50, result.ToAccount.Balance)
+
+	// 不能重複請款
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	// 請款金額不能超過保留金額
+	hold = placeRandomHold(t, store, account1, account2, 10, time.Now().Add(time.Hour))
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: 11,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: 0})
+	require.ErrorIs(t, err, ErrHoldNotFound)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	hold := placeRandomHold(t, store, account1, account2, 60, time.Now().Add(time.Hour))
+
+	released, err := store.ReleaseHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, released.Status)
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated.HeldAmount)
+
+	_, err = store.ReleaseHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+// 過期但還沒被 ExpireHoldsTx 處理的保留, 可以直接釋放
+func TestReleaseExpiredHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	hold := placeRandomHold(t, store, account1, account2, 60, time.Now().Add(-time.Second))
+
+	released, err := store.ReleaseHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, released.Status)
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated.HeldAmount)
+
+	// 已經釋放, 不會再被 ExpireHoldsTx 處理
+	holds, err := store.ExpireHoldsTx(context.Background())
+	require.NoError(t, err)
+	for _, expired := range holds {
+		require.NotEqual(t, hold.ID, expired.ID)
+	}
+}
+
+func TestExpireHoldsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	expired := placeRandomHold(t, store, account1, account2, 30, time.Now().Add(-time.Second))
+	active := placeRandomHold(t, store, account1, account2, 20, time.Now().Add(time.Hour))
+
+	// 過期的保留不能請款
+	_, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: expired.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	holds, err := store.ExpireHoldsTx(context.Background())
+	require.NoError(t, err)
+
+	ids := map[int64]bool{}
+	for _, hold := range holds {
+		require.Equal(t, HoldStatusExpired, hold.Status)
+		ids[hold.ID] = true
+	}
+	require.True(t, ids[expired.ID])
+	require.False(t, ids[active.ID])
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, active.Amount, updated.HeldAmount)
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// AccrueInterestTx accrues the interest of one day for the account
+// the accrual is recorded even if the interest is rounded to 0, so the account is not accrued again for the date.
+// ErrUniqueViolation is returned if the account is already accrued for the date.
+func (store *SQLStore) AccrueInterestTx(ctx context.Context, accountID int64, date time.Time) (InterestAccruals, error) {
+	var accrual InterestAccruals
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the balance is locked, so the interest is computed from the balance at this moment
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return TranslateNotFound(err, ErrAccountNotFound)
+		}
+		rate, err := q.GetInterestRate(ctx, account.Type)
+		if err != nil {
+			return err
+		}
+
+		amount := int64(0)
+		if account.Status == AccountStatusActive {
+			amount, err = DailyInterest(account.Balance, rate.AnnualRate)
+			if err != nil {
+				return err
+			}
+		}
+
+		accrual, err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:   account.ID,
+			AccrualDate: date,
+			Balance:     account.Balance,
+			AnnualRate:  rate.AnnualRate,
+			Amount:      amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if amount > 0 {
+			_, err = q.AddAccountAccruedInterest(ctx, AddAccountAccruedInterestParams{
+				ID:     account.ID,
+				Amount: amount,
+			})
+		}
+		return err
+	})
+	return accrual, TranslateError(err)
+}
+
+// PayInterestTx pays the accrued interest of the account with a transfer from the interest expense account,
+// and marks the accruals as paid by the transfer.
+// an empty result is returned if the account has no accrued interest.
+func (store *SQLStore) PayInterestTx(ctx context.Context, accountID int64) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		result = TransferTxResult{}
+
+		account, err := q.GetAccount(ctx, accountID)
+		if err != nil {
+			return TranslateNotFound(err, ErrAccountNotFound)
+		}
+		expense, err := q.GetInterestExpenseAccount(ctx, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		// lock both accounts in the same order as transfer, then read the accrued interest
+		accounts, err := lockActiveAccounts(ctx, q, expense.ID, account.ID)
+		if err != nil {
+			return err
+		}
+		accrued := accounts[account.ID].AccruedInterest
+		if accrued <= 0 {
+			return nil
+		}
+
+		result, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: expense.ID,
+			ToAccountID:   account.ID,
+			Amount:        accrued,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToAccount, err = q.AddAccountAccruedInterest(ctx, AddAccountAccruedInterestParams{
+			ID:     account.ID,
+			Amount: -accrued,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.PayInterestAccruals(ctx, PayInterestAccrualsParams{
+			AccountID:  account.ID,
+			TransferID: result.Transfer.ID,
+		})
+		return err
+	})
+	return result, TranslateError(err)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSavingsAccount(t *testing.T, currency string, balance int64) Accounts {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: currency,
+		Type:     AccountTypeSavings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountTypeSavings, account.Type)
+	require.Zero(t, account.AccruedInterest)
+	return account
+}
+
+func TestAccrueAndPayInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	rate, err := testQueries.GetInterestRate(context.Background(), AccountTypeSavings)
+	require.NoError(t, err)
+
+	account := createRandomSavingsAccount(t, "USD", 10000000)
+	expected, err := DailyInterest(account.Balance, rate.AnnualRate)
+	require.NoError(t, err)
+	require.Positive(t, expected)
+
+	// 兩天的利息
+	day1 := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, day := range []time.Time{day1, day2} {
+		accrual, err := store.AccrueInterestTx(context.Background(), account.ID, day)
+		require.NoError(t, err)
+		require.Equal(t, account.ID, accrual.AccountID)
+		require.Equal(t, account.Balance, accrual.Balance)
+		require.Equal(t, expected, accrual.Amount)
+		require.False(t, accrual.TransferID.Valid)
+	}
+
+	// 同一天不能重複計息
+	_, err = store.AccrueInterestTx(context.Background(), account.ID, day1)
+	require.ErrorIs(t, err, ErrUniqueViolation)
+
+	// 還沒付款, 餘額不變
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+	require.Equal(t, 2*expected, updated.AccruedInterest)
+
+	result, err := store.PayInterestTx(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2*expected, result.Transfer.Amount)
+	require.Equal(t, account.ID, result.Transfer.ToAccountID)
+	require.Equal(t, account.Balance+2*expected, result.ToAccount.Balance)
+	require.Zero(t, result.ToAccount.AccruedInterest)
+	require.Equal(t, InterestExpenseOwner, result.FromAccount.Owner)
+
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 2)
+	for _, accrual := range accruals {
+		require.True(t, accrual.TransferID.Valid)
+		require.Equal(t, result.Transfer.ID, accrual.TransferID.Int64)
+	}
+
+	// 沒有利息時不轉帳
+	result, err = store.PayInterestTx(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, result.Transfer.ID)
+}
+
+// 支票帳戶的利率是 0, 不會被計息
+func TestAccrueInterestChecking(t *testing.T) {
+	store := NewStore(testDB)
+
+	checking := fundAccount(t, createRandomAccount(t), 10000000)
+	savings := createRandomSavingsAccount(t, checking.Currency, 10000000)
+
+	date := time.Now().UTC().AddDate(1, 0, 0)
+	_, err := AccrueInterest(context.Background(), store, date)
+	require.NoError(t, err)
+
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), checking.ID)
+	require.NoError(t, err)
+	require.Empty(t, accruals)
+
+	accruals, err = testQueries.ListInterestAccruals(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+
+	// 再執行一次也不會重複計息
+	_, err = AccrueInterest(context.Background(), store, date)
+	require.NoError(t, err)
+	accruals, err = testQueries.ListInterestAccruals(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+}
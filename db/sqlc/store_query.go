@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SQLStore overrides the generated queries which return a single record,
+// so the caller gets the typed errors in error.go instead of sql.ErrNoRows or pq.Error.
+// the queries of accounts table return ErrAccountNotFound when the account doesn't exist.
+// the Queries inside a transaction are not wrapped, execTx translates the error at the end.
+
+func (store *SQLStore) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Accounts, error) {
+	result, err := store.Queries.AddAccountBalance(ctx, arg)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error) {
+	result, err := store.Queries.CreateAccount(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetAccount(ctx context.Context, id int64) (Accounts, error) {
+	result, err := store.Queries.GetAccount(ctx, id)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) GetCashAccount(ctx context.Context, currency string) (Accounts, error) {
+	result, err := store.Queries.GetCashAccount(ctx, currency)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) GetAccountForUpdate(ctx context.Context, id int64) (Accounts, error) {
+	result, err := store.Queries.GetAccountForUpdate(ctx, id)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) SetAccountOverdraftLimit(ctx context.Context, arg SetAccountOverdraftLimitParams) (Accounts, error) {
+	result, err := store.Queries.SetAccountOverdraftLimit(ctx, arg)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Accounts, error) {
+	result, err := store.Queries.SetAccountStatus(ctx, arg)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Accounts, error) {
+	result, err := store.Queries.UpdateAccountNickname(ctx, arg)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Accounts, error) {
+	result, err := store.Queries.UpdateAccount(ctx, arg)
+	return result, TranslateNotFound(err, ErrAccountNotFound)
+}
+
+func (store *SQLStore) GetEntry(ctx context.Context, id int64) (Entries, error) {
+	result, err := store.Queries.GetEntry(ctx, id)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKeys, error) {
+	result, err := store.Queries.CreateIdempotencyKey(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKeys, error) {
+	result, err := store.Queries.GetIdempotencyKey(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) BlockSession(ctx context.Context, id uuid.UUID) (Sessions, error) {
+	result, err := store.Queries.BlockSession(ctx, id)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) CreateSession(ctx context.Context, arg CreateSessionParams) (Sessions, error) {
+	result, err := store.Queries.CreateSession(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetSession(ctx context.Context, id uuid.UUID) (Sessions, error) {
+	result, err := store.Queries.GetSession(ctx, id)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetTransfer(ctx context.Context, id int64) (Transfers, error) {
+	result, err := store.Queries.GetTransfer(ctx, id)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRates, error) {
+	result, err := store.Queries.GetExchangeRate(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRates, error) {
+	result, err := store.Queries.UpsertExchangeRate(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetHold(ctx context.Context, id int64) (Holds, error) {
+	result, err := store.Queries.GetHold(ctx, id)
+	return result, TranslateNotFound(err, ErrHoldNotFound)
+}
+
+func (store *SQLStore) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfers, error) {
+	result, err := store.Queries.CreateScheduledTransfer(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfers, error) {
+	result, err := store.Queries.GetScheduledTransfer(ctx, id)
+	return result, TranslateNotFound(err, ErrScheduledTransferNotFound)
+}
+
+func (store *SQLStore) UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfers, error) {
+	result, err := store.Queries.UpdateScheduledTransfer(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) CreateUser(ctx context.Context, arg CreateUserParams) (Users, error) {
+	result, err := store.Queries.CreateUser(ctx, arg)
+	return result, TranslateError(err)
+}
+
+func (store *SQLStore) GetUser(ctx context.Context, username string) (Users, error) {
+	result, err := store.Queries.GetUser(ctx, username)
+	return result, TranslateError(err)
+}
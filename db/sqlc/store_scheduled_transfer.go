@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// a scheduled transfer is run by the scheduler:
+// the due schedule is locked, the transfer is made like TransferTx, the run is recorded,
+// and next_run_at is moved forward, all within one transaction.
+// so a schedule is never run twice, even if more than one scheduler is running.
+
+// RunScheduledTransferTxResult is the result of running a scheduled transfer
+type RunScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfers    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRuns `json:"run"`
+	// Transfer is empty if the run failed
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// rejectedTransferErrors are the errors of a transfer which can not be made,
+// the run is recorded as failed and the schedule goes on.
+// other errors, e.g. the database is not available, abort the transaction, so the schedule is retried.
+var rejectedTransferErrors = []error{
+	ErrInsufficientFunds,
+	ErrAccountNotActive,
+	ErrAccountNotFound,
+	ErrCurrencyMismatch,
+	ErrInvalidAmount,
+	ErrTransferLimitExceeded,
+}
+
+// RunScheduledTransferTx runs the scheduled transfer which is due at now
+// ErrRecordNotFound is returned if no schedule is due, or the due ones are locked by other schedulers.
+func (store *SQLStore) RunScheduledTransferTx(ctx context.Context, now time.Time) (RunScheduledTransferTxResult, error) {
+	var result RunScheduledTransferTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		result = RunScheduledTransferTxResult{}
+
+		scheduled, err := q.GetDueScheduledTransferForUpdate(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		run := CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledAt:         scheduled.NextRunAt,
+			Status:              ScheduledTransferRunSucceeded,
+		}
+		update := UpdateScheduledTransferParams{
+			ID:     scheduled.ID,
+			Amount: scheduled.Amount,
+			Status: scheduled.Status,
+		}
+
+		update.NextRunAt, err = NextScheduledRun(scheduled, now)
+		if err != nil {
+			// the schedule can never run again, it is cancelled instead of blocking the other schedules
+			run.Status = ScheduledTransferRunFailed
+			run.Error = err.Error()
+			update.Status = ScheduledTransferStatusCancelled
+			update.NextRunAt = scheduled.NextRunAt
+		} else {
+			err = savepoint(ctx, q, "scheduled_transfer", func() error {
+				var err error
+				result.Transfer, err = userTransfer(ctx, q, store.fees, TransferTxParams{
+					FromAccountID: scheduled.FromAccountID,
+					ToAccountID:   scheduled.ToAccountID,
+					Amount:        scheduled.Amount,
+				})
+				return err
+			})
+			if err != nil {
+				if !isRejectedTransfer(err) {
+					return err
+				}
+				result.Transfer = TransferTxResult{}
+				run.Status = ScheduledTransferRunFailed
+				run.Error = TranslateError(err).Error()
+			} else {
+				run.TransferID = sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}
+			}
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, run)
+		if err != nil {
+			return err
+		}
+		result.ScheduledTransfer, err = q.UpdateScheduledTransfer(ctx, update)
+		return err
+	})
+	return result, TranslateError(err)
+}
+
+func isRejectedTransfer(err error) bool {
+	err = TranslateError(err)
+	for _, rejected := range rejectedTransferErrors {
+		if errors.Is(err, rejected) {
+			return true
+		}
+	}
+	return false
+}
+
+// savepoint runs fn after a savepoint, and rolls back to the savepoint if fn fails,
+// so the transaction can go on even if a statement of fn is failed by postgres.
+func savepoint(ctx context.Context, q *Queries, name string, fn func() error) error {
+	if _, err := q.db.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if _, rbErr := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+	_, err := q.db.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
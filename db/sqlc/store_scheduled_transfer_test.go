@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, account, toAccount Accounts, amount int64, nextRunAt time.Time) ScheduledTransfers {
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:           account.Owner,
+		FromAccountID:   account.ID,
+		ToAccountID:     toAccount.ID,
+		Amount:          amount,
+		IntervalSeconds: 3600,
+		NextRunAt:       nextRunAt,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, scheduled.ID)
+	require.Equal(t, ScheduledTransferStatusActive, scheduled.Status)
+	return scheduled
+}
+
+// runScheduledTransfer runs the due schedules until the given one is run
+// the schedules created by other tests may be due at the same time.
+func runScheduledTransfer(t *testing.T, store Store, id int64, now time.Time) RunScheduledTransferTxResult {
+	for {
+		result, err := store.RunScheduledTransferTx(context.Background(), now)
+		require.NoError(t, err)
+		if result.ScheduledTransfer.ID == id {
+			return result
+		}
+	}
+}
+
+func TestRunScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	scheduled := createRandomScheduledTransfer(t, account1, account2, 60, now.Add(-time.Minute))
+
+	result := runScheduledTransfer(t, store, scheduled.ID, now)
+	require.Equal(t, ScheduledTransferRunSucceeded, result.Run.Status)
+	require.True(t, result.Run.TransferID.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Run.TransferID.Int64)
+	require.Equal(t, int64(40), result.Transfer.FromAccount.Balance)
+	require.True(t, scheduled.NextRunAt.Equal(result.Run.ScheduledAt))
+	// 下次是一小時後
+	require.True(t, scheduled.NextRunAt.Add(time.Hour).Equal(result.ScheduledTransfer.NextRunAt))
+
+	// 餘額不足, 記錄失敗但排程繼續
+	result = runScheduledTransfer(t, store, scheduled.ID, now.Add(time.Hour))
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.False(t, result.Run.TransferID.Valid)
+	require.Contains(t, result.Run.Error, ErrInsufficientFunds.Error())
+	require.True(t, scheduled.NextRunAt.Add(2*time.Hour).Equal(result.ScheduledTransfer.NextRunAt))
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(40), updated.Balance)
+
+	runs, err := testQueries.ListScheduledTransferRunsPage(context.Background(), ListScheduledTransferRunsPageParams{
+		ScheduledTransferID: scheduled.ID,
+		PageLimit:           10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, ScheduledTransferRunSucceeded, runs[0].Status)
+	require.Equal(t, ScheduledTransferRunFailed, runs[1].Status)
+}
+
+func TestRunScheduledTransferTxPaused(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	scheduled := createRandomScheduledTransfer(t, account1, account2, 10, now.Add(-time.Minute))
+
+	_, err := testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:        scheduled.ID,
+		Amount:    scheduled.Amount,
+		Status:    ScheduledTransferStatusPaused,
+		NextRunAt: scheduled.NextRunAt,
+	})
+	require.NoError(t, err)
+
+	// 暫停的排程不會被執行
+	for {
+		result, err := store.RunScheduledTransferTx(context.Background(), now)
+		if err != nil {
+			require.ErrorIs(t, err, ErrRecordNotFound)
+			break
+		}
+		require.NotEqual(t, scheduled.ID, result.ScheduledTransfer.ID)
+	}
+
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), updated.Balance)
+}
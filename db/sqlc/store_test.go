@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
+	"github.com/bank-demo/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +15,9 @@ func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 	// we will send the money from account1 to account2
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	// balance can not be negative, make sure account1 has enough money for n transfers
+	account1 = fundAccount(t, account1, 1000)
 	fmt.Println(" >>> before: ", account1.Balance, account2.Balance)
 	// write database transaction is something we must always be vary careful with
 	// must handle the concurrency carefully.
@@ -162,3 +167,286 @@ func TestTransferTx(t *testing.T) {
 	// 我們要在 account.sql 裡面新增加一個 GetAccountForUpdate
 	// 在store.go中 就要改用 GetAccountForUpdate
 }
+
+// TestTransferTxDeadlock runs transfers in both directions between 2 accounts at the same time
+// half of them from account1 to account2, the other half from account2 to account1.
+// if the accounts are not updated in a consistent order, postgres will detect a deadlock.
+func TestTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	account1 = fundAccount(t, account1, 1000)
+	account2 = fundAccount(t, account2, 1000)
+	fmt.Println(" >>> before: ", account1.Balance, account2.Balance)
+
+	n := 10
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccountID := account1.ID
+		toAccountID := account2.ID
+
+		if i%2 == 1 {
+			fromAccountID = account2.ID
+			toAccountID = account1.ID
+		}
+
+		txName := fmt.Sprintf("tx %d", i+1)
+		go func() {
+			ctx := context.WithValue(context.Background(), txKey, txName)
+			result, err := store.TransferTx(ctx, TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+			// the result fields must still be mapped to the right accounts
+			if err == nil && (result.FromAccount.ID != fromAccountID || result.ToAccount.ID != toAccountID) {
+				err = fmt.Errorf("%s: result accounts mismatch", txName)
+			}
+
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	// check the final updated balances
+	// the same amount is moved back and forth, so the balances should be the same as before
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	fmt.Println(" >>> after: ", updatedAccount1.Balance, updatedAccount2.Balance)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	require.True(t, isRetryableTxError(&pq.Error{Code: "40001"}))
+	require.True(t, isRetryableTxError(&pq.Error{Code: "40P01"}))
+	// wrapped error should be detected too
+	require.True(t, isRetryableTxError(fmt.Errorf("tx err: %w", &pq.Error{Code: "40P01"})))
+
+	require.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))
+	require.False(t, isRetryableTxError(sql.ErrNoRows))
+}
+
+func TestExecTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	amount := int64(10)
+
+	addBalance := func(q Querier) error {
+		_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: amount,
+		})
+		return err
+	}
+
+	// commit: the balance is updated
+	err := store.ExecTx(context.Background(), nil, addBalance)
+	require.NoError(t, err)
+
+	// error in fn: the transaction is rolled back, and the error is translated but still wraps the cause
+	err = store.ExecTx(context.Background(), nil, func(q Querier) error {
+		if err := addBalance(q); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// panic in fn: the transaction is rolled back, and the panic is turned into an error
+	err = store.ExecTx(context.Background(), nil, func(q Querier) error {
+		if err := addBalance(q); err != nil {
+			return err
+		}
+		panic("something wrong")
+	})
+	require.Error(t, err)
+
+	// read-only transaction can not update the balance
+	err = store.ExecTx(context.Background(), &sql.TxOptions{ReadOnly: true}, addBalance)
+	require.Error(t, err)
+
+	// serializable transaction works as usual
+	err = store.ExecTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}, addBalance)
+	require.NoError(t, err)
+
+	// only 2 of the transactions are committed
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+2*amount, updatedAccount.Balance)
+}
+
+// fundAccount sets the balance of account
+// so the transfers in test will not break the balance limit of the account
+func fundAccount(t *testing.T, account Accounts, balance int64) Accounts {
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+	require.Equal(t, balance, account.Balance)
+	return account
+}
+
+// the balance limit is checked by the constraint in db
+// so the transfer is rolled back if the from account doesn't have enough money
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 10)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// nothing is changed
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	// with overdraft limit, the balance can be negative
+	account1, err = testQueries.SetAccountOverdraftLimit(context.Background(), SetAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), account1.OverdraftLimit)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), result.FromAccount.Balance)
+}
+
+func TestTransferTxInvalidAmount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 10)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        -10,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestStoreNotFound(t *testing.T) {
+	store := NewStore(testDB)
+
+	// store returns the typed error instead of sql.ErrNoRows
+	_, err := store.GetAccount(context.Background(), 0)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.GetUser(context.Background(), util.RandomOwner())
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+// frozen or closed account can not send or receive money
+func TestTransferTxInactiveAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 100)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account2.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	// nothing is changed
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	// account not found
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   0,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestTransferTxExchange(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccountWithCurrency(t, "USD"), 1000)
+	account2 := createRandomAccountWithCurrency(t, "TWD")
+
+	// 幣別不同, 沒有匯率就不能轉帳
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	// 10 USD * 30.25 = 302.5 TWD, 只入帳 302, 0.50 是 rounding residue
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ExchangeRate:  "30.25",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.Equal(t, int64(302), result.Transfer.ToAmount)
+	require.Equal(t, "30.25", result.Transfer.ExchangeRate)
+	require.Equal(t, "0.50", result.Transfer.RoundingResidue)
+
+	require.Equal(t, int64(-10), result.FromEntry.Amount)
+	require.Equal(t, int64(302), result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-10, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+302, result.ToAccount.Balance)
+
+	// 匯率太小, 換算後是 0
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+		ExchangeRate:  "0.5",
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
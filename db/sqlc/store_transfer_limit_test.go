@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithCurrency(t, "USD")
+	account2 := createRandomAccountWithCurrency(t, "USD")
+	// 確保餘額夠轉
+	account1, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account1.ID, Amount: 100})
+	require.NoError(t, err)
+
+	// 沒有帳戶自己的限制, 用帳戶類型的限制
+	limit, err := GetEffectiveTransferLimit(context.Background(), testQueries, account1)
+	require.NoError(t, err)
+	typeLimit, err := testQueries.GetTransferLimit(context.Background(), account1.Type)
+	require.NoError(t, err)
+	require.Equal(t, typeLimit.DailyCount, limit.DailyCount)
+
+	// 單筆 5, 每天 8 元, 每天 3 筆
+	_, err = testQueries.UpsertAccountTransferLimit(context.Background(), UpsertAccountTransferLimitParams{
+		AccountID:   account1.ID,
+		MaxAmount:   5,
+		DailyAmount: 8,
+		DailyCount:  3,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteAccountTransferLimit(context.Background(), account1.ID)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+	requireLimit := func(err error, name string) {
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+		var limitErr *TransferLimitError
+		require.True(t, errors.As(err, &limitErr))
+		require.Equal(t, name, limitErr.Limit)
+		require.Equal(t, account1.ID, limitErr.AccountID)
+	}
+
+	requireLimit(transfer(6), TransferLimitMaxAmount)
+	require.NoError(t, transfer(5))
+	// 5 + 4 > 8
+	requireLimit(transfer(4), TransferLimitDailyAmount)
+	require.NoError(t, transfer(2))
+	require.NoError(t, transfer(1))
+	// 第 4 筆
+	requireLimit(transfer(1), TransferLimitDailyCount)
+
+	// 被拒絕的轉帳都 rollback 了
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-8, updated.Balance)
+
+	// 提款也算轉出, 今天的次數已經用完
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{AccountID: account1.ID, Amount: 1})
+	requireLimit(err, TransferLimitDailyCount)
+
+	// 存款不受限制
+	_, err = store.DepositTx(context.Background(), CashTxParams{AccountID: account1.ID, Amount: 1})
+	require.NoError(t, err)
+
+	// 轉入帳戶沒有限制
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+}
+
+// 請款保留金額也是轉帳, 要檢查轉出帳戶的限制
+func TestCaptureHoldTxLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccountWithCurrency(t, "USD"), 100)
+	account2 := createRandomAccountWithCurrency(t, "USD")
+
+	_, err := testQueries.UpsertAccountTransferLimit(context.Background(), UpsertAccountTransferLimitParams{
+		AccountID:   account1.ID,
+		MaxAmount:   5,
+		DailyAmount: 100,
+		DailyCount:  10,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteAccountTransferLimit(context.Background(), account1.ID)
+
+	hold := placeRandomHold(t, store, account1, account2, 6, time.Now().Add(time.Hour))
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+	var limitErr *TransferLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, TransferLimitMaxAmount, limitErr.Limit)
+
+	// 被拒絕的請款 rollback 了, 保留金額還在
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+	require.Equal(t, int64(6), updated.HeldAmount)
+
+	// 只請款限制內的金額可以成功
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-5, result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+}
+
+// 提款是轉出到現金帳戶, 也要檢查限制
+func TestWithdrawTxLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := fundAccount(t, createRandomAccountWithCurrency(t, "USD"), 100)
+
+	_, err := testQueries.UpsertAccountTransferLimit(context.Background(), UpsertAccountTransferLimitParams{
+		AccountID:   account.ID,
+		MaxAmount:   5,
+		DailyAmount: 8,
+		DailyCount:  10,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteAccountTransferLimit(context.Background(), account.ID)
+
+	withdraw := func(amount int64) error {
+		_, err := store.WithdrawTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: amount})
+		return err
+	}
+	requireLimit := func(err error, name string) {
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+		var limitErr *TransferLimitError
+		require.True(t, errors.As(err, &limitErr))
+		require.Equal(t, name, limitErr.Limit)
+	}
+
+	requireLimit(withdraw(6), TransferLimitMaxAmount)
+	require.NoError(t, withdraw(5))
+	// 5 + 4 > 8
+	requireLimit(withdraw(4), TransferLimitDailyAmount)
+
+	// 轉帳和提款一起算每天的總額, 5 + 4 > 8
+	account2 := createRandomAccountWithCurrency(t, "USD")
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   account2.ID,
+		Amount:        4,
+	})
+	requireLimit(err, TransferLimitDailyAmount)
+
+	// 被拒絕的提款都 rollback 了
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-5, updated.Balance)
+}
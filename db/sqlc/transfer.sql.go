@@ -5,26 +5,40 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    rounding_residue
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"fromAccountID"`
-	ToAccountID   int64 `json:"toAccountID"`
-	Amount        int64 `json:"amount"`
+	FromAccountID   int64  `json:"fromAccountID"`
+	ToAccountID     int64  `json:"toAccountID"`
+	Amount          int64  `json:"amount"`
+	ToAmount        int64  `json:"toAmount"`
+	ExchangeRate    string `json:"exchangeRate"`
+	RoundingResidue string `json:"roundingResidue"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error) {
-	row := q.queryRow(ctx, q.createTransferStmt, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.queryRow(ctx, q.createTransferStmt, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.RoundingResidue,
+	)
 	var i Transfers
 	err := row.Scan(
 		&i.ID,
@@ -32,12 +46,15 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RoundingResidue,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -50,33 +67,257 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfers, error)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RoundingResidue,
 	)
 	return i, err
 }
 
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
+WHERE
+    (
+        ($1::boolean AND from_account_id = $2) OR
+        ($3::boolean AND to_account_id = $2)
+    ) AND
+    (CASE WHEN to_account_id = $2 THEN to_amount ELSE amount END)
+        BETWEEN $4::bigint AND $5::bigint AND
+    created_at >= $6 AND
+    created_at < $7 AND
+    (created_at, id) > ($8::timestamptz, $9::bigint)
+ORDER BY created_at, id
+LIMIT $11
+OFFSET $10
+`
+
+type ListAccountTransfersParams struct {
+	Outgoing       bool      `json:"outgoing"`
+	AccountID      int64     `json:"accountID"`
+	Incoming       bool      `json:"incoming"`
+	MinAmount      int64     `json:"minAmount"`
+	MaxAmount      int64     `json:"maxAmount"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	AfterCreatedAt time.Time `json:"afterCreatedAt"`
+	AfterID        int64     `json:"afterID"`
+	PageOffset     int32     `json:"pageOffset"`
+	PageLimit      int32     `json:"pageLimit"`
+}
+
+// outgoing transfers are sent from the account, incoming transfers are sent to the account
+// the amount range is compared in the currency of the account,
+// so to_amount is used for the incoming transfers, it differs from amount for cross-currency transfers.
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfers, error) {
+	rows, err := q.query(ctx, q.listAccountTransfersStmt, listAccountTransfers,
+		arg.Outgoing,
+		arg.AccountID,
+		arg.Incoming,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.StartTime,
+		arg.EndTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfers
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RoundingResidue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncomingTransfers = `-- name: ListIncomingTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
+WHERE to_account_id = $1
+ORDER BY id
+LIMIT $3
+OFFSET $2
+`
+
+type ListIncomingTransfersParams struct {
+	AccountID  int64 `json:"accountID"`
+	PageOffset int32 `json:"pageOffset"`
+	PageLimit  int32 `json:"pageLimit"`
+}
+
+func (q *Queries) ListIncomingTransfers(ctx context.Context, arg ListIncomingTransfersParams) ([]Transfers, error) {
+	rows, err := q.query(ctx, q.listIncomingTransfersStmt, listIncomingTransfers, arg.AccountID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfers
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RoundingResidue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingTransfers = `-- name: ListOutgoingTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
+WHERE from_account_id = $1
+ORDER BY id
+LIMIT $3
+OFFSET $2
+`
+
+type ListOutgoingTransfersParams struct {
+	AccountID  int64 `json:"accountID"`
+	PageOffset int32 `json:"pageOffset"`
+	PageLimit  int32 `json:"pageLimit"`
+}
+
+func (q *Queries) ListOutgoingTransfers(ctx context.Context, arg ListOutgoingTransfersParams) ([]Transfers, error) {
+	rows, err := q.query(ctx, q.listOutgoingTransfersStmt, listOutgoingTransfers, arg.AccountID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfers
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RoundingResidue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
 WHERE
     from_account_id = $1 OR
-    to_account_id = $2
+    to_account_id = $1
 ORDER BY id
 LIMIT $3
-OFFSET $4
+OFFSET $2
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"fromAccountID"`
-	ToAccountID   int64 `json:"toAccountID"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	AccountID  int64 `json:"accountID"`
+	PageOffset int32 `json:"pageOffset"`
+	PageLimit  int32 `json:"pageLimit"`
 }
 
+// all transfers touching the account, sent from or to it
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfers, error) {
-	rows, err := q.query(ctx, q.listTransfersStmt, listTransfers,
-		arg.FromAccountID,
-		arg.ToAccountID,
-		arg.Limit,
-		arg.Offset,
+	rows, err := q.query(ctx, q.listTransfersStmt, listTransfers, arg.AccountID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfers
+	for rows.Next() {
+		var i Transfers
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RoundingResidue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersBetween = `-- name: ListTransfersBetween :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rounding_residue FROM transfers
+WHERE
+    (from_account_id = $1 AND to_account_id = $2) OR
+    (from_account_id = $2 AND to_account_id = $1)
+ORDER BY id
+LIMIT $4
+OFFSET $3
+`
+
+type ListTransfersBetweenParams struct {
+	Account1ID int64 `json:"account1ID"`
+	Account2ID int64 `json:"account2ID"`
+	PageOffset int32 `json:"pageOffset"`
+	PageLimit  int32 `json:"pageLimit"`
+}
+
+// transfers between 2 accounts in both directions,
+// each side of OR uses the index on (from_account_id, to_account_id)
+func (q *Queries) ListTransfersBetween(ctx context.Context, arg ListTransfersBetweenParams) ([]Transfers, error) {
+	rows, err := q.query(ctx, q.listTransfersBetweenStmt, listTransfersBetween,
+		arg.Account1ID,
+		arg.Account2ID,
+		arg.PageOffset,
+		arg.PageLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -91,6 +332,9 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RoundingResidue,
 		); err != nil {
 			return nil, err
 		}
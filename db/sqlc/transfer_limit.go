@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// limits of the outgoing transfers of an account, see the migration 000014_add_transfer_limits
+// the limits are checked by TransferTx and WithdrawTx after the transfer is created, within the same transaction,
+// the from account is locked, so the transfers of the same account are counted one by one.
+const (
+	TransferLimitMaxAmount   = "max_amount"
+	TransferLimitDailyAmount = "daily_amount"
+	TransferLimitDailyCount  = "daily_count"
+)
+
+// ErrTransferLimitExceeded is returned if a transfer is more than the limits of the from account
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// TransferLimitError tells which limit of the account is exceeded
+// errors.Is(err, ErrTransferLimitExceeded) is true.
+type TransferLimitError struct {
+	AccountID int64
+	// Limit is one of TransferLimitMaxAmount, TransferLimitDailyAmount and TransferLimitDailyCount
+	Limit string
+	Max   int64
+}
+
+func (e *TransferLimitError) Error() string {
+	return fmt.Sprintf("%s: %s of account [%d] is %d", ErrTransferLimitExceeded, e.Limit, e.AccountID, e.Max)
+}
+
+func (e *TransferLimitError) Is(target error) bool {
+	return target == ErrTransferLimitExceeded
+}
+
+// GetEffectiveTransferLimit returns the limits of the account,
+// the limits of the account itself if there are, or the limits of its type.
+// all limits are 0 if neither is found.
+func GetEffectiveTransferLimit(ctx context.Context, q Querier, account Accounts) (AccountTransferLimits, error) {
+	limit, err := q.GetAccountTransferLimit(ctx, account.ID)
+	if err == nil {
+		return limit, nil
+	}
+	if !errors.Is(TranslateError(err), ErrRecordNotFound) {
+		return limit, err
+	}
+
+	typeLimit, err := q.GetTransferLimit(ctx, account.Type)
+	if err != nil {
+		if errors.Is(TranslateError(err), ErrRecordNotFound) {
+			return AccountTransferLimits{AccountID: account.ID}, nil
+		}
+		return limit, err
+	}
+	return AccountTransferLimits{
+		AccountID:   account.ID,
+		MaxAmount:   typeLimit.MaxAmount,
+		DailyAmount: typeLimit.DailyAmount,
+		DailyCount:  typeLimit.DailyCount,
+		UpdatedAt:   typeLimit.UpdatedAt,
+	}, nil
+}
+
+// checkTransferLimit returns a TransferLimitError if the transfer exceeds the limits of the from account
+// the transfer must be already created, so it is counted in the total of today.
+func checkTransferLimit(ctx context.Context, q *Queries, account Accounts, transfer Transfers) error {
+	limit, err := GetEffectiveTransferLimit(ctx, q, account)
+	if err != nil {
+		return err
+	}
+
+	if limit.MaxAmount > 0 && transfer.Amount > limit.MaxAmount {
+		return &TransferLimitError{AccountID: account.ID, Limit: TransferLimitMaxAmount, Max: limit.MaxAmount}
+	}
+	if limit.DailyAmount == 0 && limit.DailyCount == 0 {
+		return nil
+	}
+
+	createdAt := transfer.CreatedAt.UTC()
+	today, err := q.GetDailyOutgoingTransfers(ctx, GetDailyOutgoingTransfersParams{
+		AccountID: account.ID,
+		Since:     time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return err
+	}
+	if limit.DailyAmount > 0 && today.TotalAmount > limit.DailyAmount {
+		return &TransferLimitError{AccountID: account.ID, Limit: TransferLimitDailyAmount, Max: limit.DailyAmount}
+	}
+	if limit.DailyCount > 0 && today.TransferCount > limit.DailyCount {
+		return &TransferLimitError{AccountID: account.ID, Limit: TransferLimitDailyCount, Max: limit.DailyCount}
+	}
+	return nil
+}
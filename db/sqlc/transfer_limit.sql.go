@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: transfer_limit.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const deleteAccountTransferLimit = `-- name: DeleteAccountTransferLimit :exec
+DELETE FROM account_transfer_limits
+WHERE account_id = $1
+`
+
+func (q *Queries) DeleteAccountTransferLimit(ctx context.Context, accountID int64) error {
+	_, err := q.exec(ctx, q.deleteAccountTransferLimitStmt, deleteAccountTransferLimit, accountID)
+	return err
+}
+
+const getAccountTransferLimit = `-- name: GetAccountTransferLimit :one
+SELECT account_id, max_amount, daily_amount, daily_count, updated_at FROM account_transfer_limits
+WHERE account_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountTransferLimit(ctx context.Context, accountID int64) (AccountTransferLimits, error) {
+	row := q.queryRow(ctx, q.getAccountTransferLimitStmt, getAccountTransferLimit, accountID)
+	var i AccountTransferLimits
+	err := row.Scan(
+		&i.AccountID,
+		&i.MaxAmount,
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getDailyOutgoingTransfers = `-- name: GetDailyOutgoingTransfers :one
+SELECT
+    COALESCE(SUM(t.amount), 0)::bigint AS total_amount,
+    COUNT(*) AS transfer_count
+FROM transfers t
+JOIN accounts a ON a.id = t.to_account_id
+WHERE
+    t.from_account_id = $1 AND
+    t.created_at >= $2::timestamptz AND
+    a.owner NOT IN ('interest', 'fees')
+`
+
+type GetDailyOutgoingTransfersParams struct {
+	AccountID int64     `json:"accountID"`
+	Since     time.Time `json:"since"`
+}
+
+type GetDailyOutgoingTransfersRow struct {
+	TotalAmount   int64 `json:"totalAmount"`
+	TransferCount int64 `json:"transferCount"`
+}
+
+// withdrawals to the cash accounts are counted, the fees charged by the bank are not.
+func (q *Queries) GetDailyOutgoingTransfers(ctx context.Context, arg GetDailyOutgoingTransfersParams) (GetDailyOutgoingTransfersRow, error) {
+	row := q.queryRow(ctx, q.getDailyOutgoingTransfersStmt, getDailyOutgoingTransfers, arg.AccountID, arg.Since)
+	var i GetDailyOutgoingTransfersRow
+	err := row.Scan(&i.TotalAmount, &i.TransferCount)
+	return i, err
+}
+
+const getTransferLimit = `-- name: GetTransferLimit :one
+SELECT account_type, max_amount, daily_amount, daily_count, updated_at FROM transfer_limits
+WHERE account_type = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferLimit(ctx context.Context, accountType string) (TransferLimits, error) {
+	row := q.queryRow(ctx, q.getTransferLimitStmt, getTransferLimit, accountType)
+	var i TransferLimits
+	err := row.Scan(
+		&i.AccountType,
+		&i.MaxAmount,
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertAccountTransferLimit = `-- name: UpsertAccountTransferLimit :one
+INSERT INTO account_transfer_limits (
+    account_id,
+    max_amount,
+    daily_amount,
+    daily_count
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (account_id) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    daily_count = EXCLUDED.daily_count,
+    updated_at = now()
+RETURNING account_id, max_amount, daily_amount, daily_count, updated_at
+`
+
+type UpsertAccountTransferLimitParams struct {
+	AccountID   int64 `json:"accountID"`
+	MaxAmount   int64 `json:"maxAmount"`
+	DailyAmount int64 `json:"dailyAmount"`
+	DailyCount  int64 `json:"dailyCount"`
+}
+
+func (q *Queries) UpsertAccountTransferLimit(ctx context.Context, arg UpsertAccountTransferLimitParams) (AccountTransferLimits, error) {
+	row := q.queryRow(ctx, q.upsertAccountTransferLimitStmt, upsertAccountTransferLimit,
+		arg.AccountID,
+		arg.MaxAmount,
+		arg.DailyAmount,
+		arg.DailyCount,
+	)
+	var i AccountTransferLimits
+	err := row.Scan(
+		&i.AccountID,
+		&i.MaxAmount,
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTransferLimit = `-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    account_type,
+    max_amount,
+    daily_amount,
+    daily_count
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (account_type) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    daily_count = EXCLUDED.daily_count,
+    updated_at = now()
+RETURNING account_type, max_amount, daily_amount, daily_count, updated_at
+`
+
+type UpsertTransferLimitParams struct {
+	AccountType string `json:"accountType"`
+	MaxAmount   int64  `json:"maxAmount"`
+	DailyAmount int64  `json:"dailyAmount"`
+	DailyCount  int64  `json:"dailyCount"`
+}
+
+func (q *Queries) UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimits, error) {
+	row := q.queryRow(ctx, q.upsertTransferLimitStmt, upsertTransferLimit,
+		arg.AccountType,
+		arg.MaxAmount,
+		arg.DailyAmount,
+		arg.DailyCount,
+	)
+	var i TransferLimits
+	err := row.Scan(
+		&i.AccountType,
+		&i.MaxAmount,
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
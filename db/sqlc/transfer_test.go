@@ -0,0 +1,205 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransfer(t *testing.T, account1, account2 Accounts, amount int64) Transfers {
+	arg := CreateTransferParams{
+		FromAccountID:   account1.ID,
+		ToAccountID:     account2.ID,
+		Amount:          amount,
+		ToAmount:        amount,
+		ExchangeRate:    "1",
+		RoundingResidue: "0",
+	}
+
+	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, transfer)
+
+	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
+	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
+	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.ToAmount, transfer.ToAmount)
+	require.NotZero(t, transfer.ID)
+	require.NotZero(t, transfer.CreatedAt)
+
+	return transfer
+}
+
+// allTransfers is the filter of ListAccountTransfers which matches every transfer of the account
+func allTransfers(accountID int64) ListAccountTransfersParams {
+	return ListAccountTransfersParams{
+		AccountID: accountID,
+		Incoming:  true,
+		Outgoing:  true,
+		MinAmount: 0,
+		MaxAmount: math.MaxInt64,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+		PageLimit: 10,
+	}
+}
+
+func TestListAccountTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	out1 := createRandomTransfer(t, account1, account2, 10)
+	out2 := createRandomTransfer(t, account1, account3, 20)
+	in1 := createRandomTransfer(t, account2, account1, 30)
+	// not related to account1
+	createRandomTransfer(t, account2, account3, 40)
+
+	transfers, err := testQueries.ListAccountTransfers(context.Background(), allTransfers(account1.ID))
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{out1, out2, in1}, transfers)
+
+	// outgoing only
+	arg := allTransfers(account1.ID)
+	arg.Incoming = false
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{out1, out2}, transfers)
+
+	// incoming only
+	arg = allTransfers(account1.ID)
+	arg.Outgoing = false
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{in1}, transfers)
+
+	// amount range
+	arg = allTransfers(account1.ID)
+	arg.MinAmount = 15
+	arg.MaxAmount = 30
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{out2, in1}, transfers)
+
+	// time range in the future
+	arg = allTransfers(account1.ID)
+	arg.StartTime = time.Now().Add(time.Minute)
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	// pagination
+	arg = allTransfers(account1.ID)
+	arg.PageLimit = 2
+	arg.PageOffset = 1
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{out2, in1}, transfers)
+}
+
+// 跨幣別的轉入, 金額範圍用轉入帳戶幣別的 to_amount 比較
+func TestListAccountTransfersCrossCurrency(t *testing.T) {
+	usd := createRandomAccountWithCurrency(t, "USD")
+	twd := createRandomAccountWithCurrency(t, "TWD")
+
+	// 10 USD -> 300 TWD
+	transfer, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID:   usd.ID,
+		ToAccountID:     twd.ID,
+		Amount:          10,
+		ToAmount:        300,
+		ExchangeRate:    "30",
+		RoundingResidue: "0",
+	})
+	require.NoError(t, err)
+
+	arg := allTransfers(twd.ID)
+	arg.MinAmount = 100
+	arg.MaxAmount = 1000
+	transfers, err := testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{transfer}, transfers)
+
+	// amount of the from account is not used for the to account
+	arg.MinAmount = 1
+	arg.MaxAmount = 20
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	// the from account still uses amount
+	arg = allTransfers(usd.ID)
+	arg.MinAmount = 1
+	arg.MaxAmount = 20
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{transfer}, transfers)
+}
+
+// account1 <-> account2 and account1 -> account3, account3 -> account2 is not related to account1
+func TestListTransfers(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	t12 := createRandomTransfer(t, account1, account2, 10)
+	t21 := createRandomTransfer(t, account2, account1, 20)
+	t13 := createRandomTransfer(t, account1, account3, 30)
+	t32 := createRandomTransfer(t, account3, account2, 40)
+
+	// touching account1
+	transfers, err := store.ListTransfers(context.Background(), ListTransfersParams{
+		AccountID: account1.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{t12, t21, t13}, transfers)
+
+	// both directions between account1 and account2, the order of arguments doesn't matter
+	for _, arg := range []ListTransfersBetweenParams{
+		{Account1ID: account1.ID, Account2ID: account2.ID, PageLimit: 10},
+		{Account1ID: account2.ID, Account2ID: account1.ID, PageLimit: 10},
+	} {
+		transfers, err = store.ListTransfersBetween(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, []Transfers{t12, t21}, transfers)
+	}
+
+	transfers, err = store.ListTransfersBetween(context.Background(), ListTransfersBetweenParams{
+		Account1ID: account2.ID,
+		Account2ID: account3.ID,
+		PageLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{t32}, transfers)
+
+	// outgoing only
+	transfers, err = store.ListOutgoingTransfers(context.Background(), ListOutgoingTransfersParams{
+		AccountID: account1.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{t12, t13}, transfers)
+
+	// incoming only
+	transfers, err = store.ListIncomingTransfers(context.Background(), ListIncomingTransfersParams{
+		AccountID: account2.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{t12, t32}, transfers)
+
+	// pagination
+	transfers, err = store.ListTransfers(context.Background(), ListTransfersParams{
+		AccountID:  account1.ID,
+		PageLimit:  2,
+		PageOffset: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Transfers{t21, t13}, transfers)
+}
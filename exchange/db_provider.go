@@ -0,0 +1,42 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// DBProvider is a Provider with the rates kept in the exchange_rates table
+// the rates can be updated by UpsertExchangeRate without restarting the server.
+type DBProvider struct {
+	store db.Querier
+}
+
+// NewDBProvider creates a new DBProvider
+func NewDBProvider(store db.Querier) Provider {
+	return &DBProvider{
+		store: store,
+	}
+}
+
+// GetRate returns the rate of the currencies in db
+func (provider *DBProvider) GetRate(ctx context.Context, fromCurrency string, toCurrency string) (string, error) {
+	if fromCurrency == toCurrency {
+		return "1", nil
+	}
+
+	rate, err := provider.store.GetExchangeRate(ctx, db.GetExchangeRateParams{
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+	})
+	if err != nil {
+		// the store may be *db.Queries which returns sql.ErrNoRows
+		if errors.Is(db.TranslateError(err), db.ErrRecordNotFound) {
+			return "", fmt.Errorf("%w: %s/%s", ErrRateNotFound, fromCurrency, toCurrency)
+		}
+		return "", err
+	}
+	return rate.Rate, nil
+}
@@ -0,0 +1,40 @@
+package exchange
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	provider := NewDBProvider(store)
+
+	arg := db.GetExchangeRateParams{FromCurrency: "USD", ToCurrency: "TWD"}
+	store.EXPECT().GetExchangeRate(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.ExchangeRates{
+		FromCurrency: "USD",
+		ToCurrency:   "TWD",
+		Rate:         "30.25",
+	}, nil)
+	rate, err := provider.GetRate(context.Background(), "USD", "TWD")
+	require.NoError(t, err)
+	require.Equal(t, "30.25", rate)
+
+	// the same currency doesn't need db
+	rate, err = provider.GetRate(context.Background(), "TWD", "TWD")
+	require.NoError(t, err)
+	require.Equal(t, "1", rate)
+
+	arg = db.GetExchangeRateParams{FromCurrency: "TWD", ToCurrency: "USD"}
+	store.EXPECT().GetExchangeRate(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.ExchangeRates{}, sql.ErrNoRows)
+	_, err = provider.GetRate(context.Background(), "TWD", "USD")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
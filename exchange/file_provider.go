@@ -0,0 +1,28 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewFileProvider creates a StaticProvider with the rates in a JSON file
+// the file is read only once, the server must be restarted to use the new rates.
+// e.g.
+//
+//	{
+//	  "USD/TWD": "30.25",
+//	  "TWD/USD": "0.0331"
+//	}
+func NewFileProvider(path string) (Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exchange rates file: %w", err)
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("cannot parse exchange rates file: %w", err)
+	}
+	return NewStaticProvider(rates)
+}
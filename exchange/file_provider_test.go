@@ -0,0 +1,32 @@
+package exchange
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"USD/TWD": "30.25"}`), 0600)
+	require.NoError(t, err)
+
+	provider, err := NewFileProvider(path)
+	require.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), "USD", "TWD")
+	require.NoError(t, err)
+	require.Equal(t, "30.25", rate)
+
+	// rates must be strings, so no precision is lost by float
+	err = os.WriteFile(path, []byte(`{"USD/TWD": 30.25}`), 0600)
+	require.NoError(t, err)
+	_, err = NewFileProvider(path)
+	require.Error(t, err)
+
+	_, err = NewFileProvider(filepath.Join(t.TempDir(), "not_found.json"))
+	require.Error(t, err)
+}
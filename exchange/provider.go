@@ -0,0 +1,18 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRateNotFound is returned if the provider doesn't have the rate of the currencies
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Provider is an interface for getting the exchange rate between 2 currencies
+// the server doesn't care where the rates come from,
+// they can be fixed in config, loaded from a file, or kept in the exchange_rates table.
+type Provider interface {
+	// GetRate returns the amount of toCurrency for 1 unit of fromCurrency as a decimal string, e.g. "30.25"
+	// the rate of the same currency is always "1".
+	GetRate(ctx context.Context, fromCurrency string, toCurrency string) (string, error)
+}
@@ -0,0 +1,53 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// StaticProvider is a Provider with fixed rates
+// the rate of each direction must be given, e.g. both "USD/TWD" and "TWD/USD",
+// because the inverse of a decimal rate may not be a decimal.
+type StaticProvider struct {
+	rates map[string]string
+}
+
+// NewStaticProvider creates a new StaticProvider
+// the key of rates is the currencies separated by slash, e.g. {"USD/TWD": "30.25"}
+func NewStaticProvider(rates map[string]string) (Provider, error) {
+	provider := &StaticProvider{
+		rates: make(map[string]string, len(rates)),
+	}
+
+	for pair, rate := range rates {
+		currencies := strings.Split(pair, "/")
+		if len(currencies) != 2 || currencies[0] == "" || currencies[1] == "" {
+			return nil, fmt.Errorf("invalid currency pair: %q", pair)
+		}
+		if _, err := db.ParseExchangeRate(rate); err != nil {
+			return nil, fmt.Errorf("invalid rate of %s: %w", pair, err)
+		}
+		provider.rates[ratePair(currencies[0], currencies[1])] = rate
+	}
+	return provider, nil
+}
+
+// GetRate returns the fixed rate of the currencies
+func (provider *StaticProvider) GetRate(ctx context.Context, fromCurrency string, toCurrency string) (string, error) {
+	if fromCurrency == toCurrency {
+		return "1", nil
+	}
+
+	rate, ok := provider.rates[ratePair(fromCurrency, toCurrency)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s/%s", ErrRateNotFound, fromCurrency, toCurrency)
+	}
+	return rate, nil
+}
+
+func ratePair(fromCurrency string, toCurrency string) string {
+	return strings.ToUpper(fromCurrency) + "/" + strings.ToUpper(toCurrency)
+}
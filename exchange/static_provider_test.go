@@ -0,0 +1,54 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticProvider(t *testing.T) {
+	provider, err := NewStaticProvider(map[string]string{
+		"USD/TWD": "30.25",
+		"twd/usd": "0.0331",
+	})
+	require.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), "USD", "TWD")
+	require.NoError(t, err)
+	require.Equal(t, "30.25", rate)
+
+	// currency code is case insensitive
+	rate, err = provider.GetRate(context.Background(), "TWD", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "0.0331", rate)
+
+	rate, err = provider.GetRate(context.Background(), "USD", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "1", rate)
+
+	_, err = provider.GetRate(context.Background(), "USD", "JPY")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestStaticProviderInvalidRates(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rates map[string]string
+	}{
+		{name: "InvalidPair", rates: map[string]string{"USDTWD": "30"}},
+		{name: "EmptyCurrency", rates: map[string]string{"USD/": "30"}},
+		{name: "InvalidRate", rates: map[string]string{"USD/TWD": "abc"}},
+		{name: "ZeroRate", rates: map[string]string{"USD/TWD": "0"}},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewStaticProvider(tc.rates)
+			require.Error(t, err)
+			require.Nil(t, provider)
+		})
+	}
+}
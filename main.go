@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"log"
+	"os"
+	"time"
 
 	"github.com/bank-demo/api"
 	db "github.com/bank-demo/db/sqlc"
+	"github.com/bank-demo/outbox"
+	"github.com/bank-demo/scheduler"
 	"github.com/bank-demo/util"
 	_ "github.com/lib/pq"
 )
@@ -30,7 +37,60 @@ func main() {
 	// if connection to DB success, use the conn as db.NewStore()'s input
 	// and use store as server's input, so the server can handle request about DB
 	store := db.NewStore(conn)
-	server := api.NewServer(store)
+	if config.FeeScheduleFile != "" {
+		fees, err := db.LoadFeeScheduleFile(config.FeeScheduleFile)
+		if err != nil {
+			log.Fatal("cannot load fee schedule: ", err)
+		}
+		store = db.NewStoreWithFees(conn, fees)
+	}
+
+	// subcommands, e.g. bank-demo reconcile -fail
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			runReconcile(store, os.Args[2:])
+			return
+		case "expire-holds":
+			runExpireHolds(store)
+			return
+		case "accrue-interest":
+			runAccrueInterest(store, os.Args[2:])
+			return
+		case "pay-interest":
+			runPayInterest(store)
+			return
+		case "snapshot-balances":
+			runSnapshotBalances(store, os.Args[2:])
+			return
+		default:
+			log.Fatalf("unknown command: %s", os.Args[1])
+		}
+	}
+
+	server, err := api.NewServer(config, store)
+	if err != nil {
+		log.Fatal("cannot create server: ", err)
+	}
+
+	// the scheduled transfers are run in the background of the server process
+	if config.SchedulerInterval > 0 {
+		go scheduler.NewScheduler(store, config.SchedulerInterval).Start(context.Background())
+	}
+
+	// the holds past expires_at are expired in the background of the server process
+	if config.HoldExpiryInterval > 0 {
+		go scheduler.NewHoldExpirer(store, config.HoldExpiryInterval).Start(context.Background())
+	}
+
+	// the outbox events are published to the sinks in the background of the server process
+	if config.OutboxInterval > 0 && config.OutboxSinks != "" {
+		sinks, err := outbox.NewSinks(config.OutboxSinks)
+		if err != nil {
+			log.Fatal("cannot create outbox sinks: ", err)
+		}
+		go outbox.NewRelay(store, sinks, config.OutboxInterval).Start(context.Background())
+	}
 
 	// start the HTTP server
 	err = server.Start(config.ServerAddress)
@@ -38,3 +98,83 @@ func main() {
 		log.Fatal("cannot start HTTP server: ", err)
 	}
 }
+
+// runReconcile prints the reconciliation report of the ledger in JSON
+// with -fail, it exits with status 1 if any problem is found, so it can be used in nightly checks.
+func runReconcile(store db.Store, args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	fail := flags.Bool("fail", false, "exit with non-zero status if the ledger is not consistent")
+	flags.Parse(args)
+
+	report, err := db.Reconcile(context.Background(), store)
+	if err != nil {
+		log.Fatal("cannot reconcile: ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("cannot write report: ", err)
+	}
+
+	if *fail && !report.OK() {
+		os.Exit(1)
+	}
+}
+
+// runExpireHolds releases the holds which are not captured before they expire
+// the server process does it every HOLD_EXPIRY_INTERVAL, this command is for running it by hand or by cron.
+func runExpireHolds(store db.Store) {
+	holds, err := store.ExpireHoldsTx(context.Background())
+	if err != nil {
+		log.Fatal("cannot expire holds: ", err)
+	}
+	log.Printf("%d holds expired", len(holds))
+}
+
+// runAccrueInterest accrues the interest of one day, it should be run once a day
+// the date is today in UTC by default, e.g. bank-demo accrue-interest -date 2021-10-31
+func runAccrueInterest(store db.Store, args []string) {
+	flags := flag.NewFlagSet("accrue-interest", flag.ExitOnError)
+	date := flags.String("date", time.Now().UTC().Format("2006-01-02"), "the date of the interest, YYYY-MM-DD")
+	flags.Parse(args)
+
+	day, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		log.Fatal("invalid date: ", err)
+	}
+
+	n, err := db.AccrueInterest(context.Background(), store, day)
+	if err != nil {
+		log.Fatal("cannot accrue interest: ", err)
+	}
+	log.Printf("interest of %s accrued for %d accounts", *date, n)
+}
+
+// runPayInterest pays the accrued interest into the balance, it should be run once a month
+func runPayInterest(store db.Store) {
+	n, err := db.PayInterest(context.Background(), store)
+	if err != nil {
+		log.Fatal("cannot pay interest: ", err)
+	}
+	log.Printf("interest paid to %d accounts", n)
+}
+
+// runSnapshotBalances saves the balance of all accounts at the beginning of a day, it should be run once a day
+// the date is today in UTC by default, e.g. bank-demo snapshot-balances -date 2021-10-31
+func runSnapshotBalances(store db.Store, args []string) {
+	flags := flag.NewFlagSet("snapshot-balances", flag.ExitOnError)
+	date := flags.String("date", time.Now().UTC().Format("2006-01-02"), "the date of the snapshot, YYYY-MM-DD")
+	flags.Parse(args)
+
+	day, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		log.Fatal("invalid date: ", err)
+	}
+
+	n, err := db.SnapshotBalances(context.Background(), store, day)
+	if err != nil {
+		log.Fatal("cannot snapshot balances: ", err)
+	}
+	log.Printf("balances at the beginning of %s saved for %d accounts", *date, n)
+}
@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// defaultBatchSize is the max number of events claimed at a time
+const defaultBatchSize = 100
+
+// defaultLease is how long the claimed events are skipped by other relays,
+// it should be longer than publishing a batch, or the events may be published twice by two relays.
+const defaultLease = 5 * time.Minute
+
+// defaultMaxAttempts is the number of failures after which an event is dead,
+// a dead event is not published anymore, so an event which can never be published doesn't block the others.
+const defaultMaxAttempts = 10
+
+// Relay publishes the events in the outbox_events table to the sinks in the background of the server process
+// the events are published in order of id. an event is published to all sinks before published_at is saved,
+// so it is published again if the relay fails in between, that is at-least-once delivery.
+// no transaction is open while the events are sent to the sinks,
+// the events are claimed with a lease, then each event is marked by its own statement after it is published.
+type Relay struct {
+	store       db.Store
+	sinks       []Sink
+	interval    time.Duration
+	batchSize   int32
+	lease       time.Duration
+	maxAttempts int32
+}
+
+// NewRelay creates a new Relay, interval is the time between the checks of unpublished events
+func NewRelay(store db.Store, sinks []Sink, interval time.Duration) *Relay {
+	return &Relay{
+		store:       store,
+		sinks:       sinks,
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		lease:       defaultLease,
+		maxAttempts: defaultMaxAttempts,
+	}
+}
+
+// Start runs the relay until ctx is done, then closes the sinks
+func (relay *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+	defer relay.close()
+
+	for {
+		n, err := relay.PublishPending(ctx)
+		if err != nil {
+			log.Printf("cannot publish outbox events: %v", err)
+		}
+		if n > 0 {
+			log.Printf("%d outbox events published", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes the unpublished events batch by batch until none is left,
+// and returns the number of events published.
+// it stops at the first event which can not be published, so the order of events is kept,
+// the event is tried again in the next run.
+func (relay *Relay) PublishPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, more, err := relay.publishBatch(ctx)
+		total += n
+		if err != nil || !more {
+			return total, err
+		}
+	}
+}
+
+// publishBatch claims a batch of events and publishes them,
+// the claimed events are skipped by another relay until the lease expires.
+// more is true if the batch is full, there may be more events to publish.
+func (relay *Relay) publishBatch(ctx context.Context) (n int, more bool, err error) {
+	events, err := relay.store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+		LockedUntil: time.Now().Add(relay.lease),
+		BatchSize:   relay.batchSize,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	for i, event := range events {
+		publishErr := relay.publish(ctx, newEvent(event))
+		if publishErr != nil {
+			// the failure is saved, and the events after it wait for the next run
+			failed, err := relay.store.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+				ID:          event.ID,
+				LastError:   publishErr.Error(),
+				MaxAttempts: relay.maxAttempts,
+			})
+			if err != nil {
+				return n, false, err
+			}
+			// unless the event is dead, then it is skipped and the events after it go on
+			if failed.FailedAt.Valid {
+				log.Printf("outbox event [%d] is dead after %d attempts, it is not published anymore: %v",
+					event.ID, failed.Attempts, publishErr)
+				continue
+			}
+			if rest := events[i+1:]; len(rest) > 0 {
+				err = relay.store.ReleaseOutboxEvents(ctx, eventIDs(rest))
+				if err != nil {
+					return n, false, err
+				}
+			}
+			return n, false, fmt.Errorf("cannot publish event [%d]: %w", event.ID, publishErr)
+		}
+
+		_, err = relay.store.MarkOutboxEventPublished(ctx, event.ID)
+		if err != nil {
+			return n, false, err
+		}
+		n++
+	}
+	return n, len(events) == int(relay.batchSize), nil
+}
+
+func eventIDs(events []db.OutboxEvents) []int64 {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// publish sends the event to all sinks
+func (relay *Relay) publish(ctx context.Context, event Event) error {
+	for _, sink := range relay.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (relay *Relay) close() {
+	for _, sink := range relay.sinks {
+		if err := sink.Close(); err != nil {
+			log.Printf("cannot close outbox sink: %v", err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink keeps the published events, it fails if err is set,
+// only for the event of failID if it is not 0.
+type memorySink struct {
+	events []Event
+	err    error
+	failID int64
+}
+
+func (sink *memorySink) Publish(ctx context.Context, event Event) error {
+	if sink.err != nil && (sink.failID == 0 || sink.failID == event.ID) {
+		return sink.err
+	}
+	sink.events = append(sink.events, event)
+	return nil
+}
+
+func (sink *memorySink) Close() error {
+	return nil
+}
+
+func randomOutboxEvent(id int64) db.OutboxEvents {
+	return db.OutboxEvents{
+		ID:            id,
+		AggregateType: "transfer",
+		AggregateID:   id * 10,
+		EventType:     "transfer.created",
+		Payload:       []byte(`{"id": 1}`),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// locked_until of the claim depends on the current time, so gomock.Eq() can not be used.
+// eqBatchSizeMatcher checks the batch size, and the lease is not expired yet
+type eqBatchSizeMatcher struct {
+	batchSize int32
+}
+
+func (e eqBatchSizeMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.ClaimOutboxEventsParams)
+	if !ok {
+		return false
+	}
+	return arg.BatchSize == e.batchSize && arg.LockedUntil.After(time.Now())
+}
+
+func (e eqBatchSizeMatcher) String() string {
+	return fmt.Sprintf("matches batch size %v", e.batchSize)
+}
+
+func eqBatchSize(batchSize int32) gomock.Matcher {
+	return eqBatchSizeMatcher{batchSize}
+}
+
+func TestPublishPending(t *testing.T) {
+	events := []db.OutboxEvents{randomOutboxEvent(1), randomOutboxEvent(2), randomOutboxEvent(3)}
+
+	testCases := []struct {
+		name      string
+		sinkErr   error
+		failID    int64
+		buildStub func(store *mockdb.MockStore)
+		check     func(t *testing.T, sink *memorySink, n int, err error)
+	}{
+		{
+			// a full batch means there may be more events, so the next batch is checked
+			name: "TwoBatches",
+			buildStub: func(store *mockdb.MockStore) {
+				// no transaction is open while the events are published
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				// RETURNING of the claim is not in order of id
+				gomock.InOrder(
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), eqBatchSize(2)).Return([]db.OutboxEvents{events[1], events[0]}, nil),
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), eqBatchSize(2)).Return(events[2:], nil),
+				)
+				for _, event := range events {
+					store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+				}
+			},
+			check: func(t *testing.T, sink *memorySink, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, n)
+				require.Len(t, sink.events, 3)
+				for i, event := range sink.events {
+					require.Equal(t, events[i].ID, event.ID)
+					require.Equal(t, events[i].EventType, event.EventType)
+					require.JSONEq(t, string(events[i].Payload), string(event.Payload))
+				}
+			},
+		},
+		{
+			name: "NoEvent",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, sink *memorySink, n int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		{
+			// the failure is saved, and the events after it are released for the next run
+			name:    "SinkError",
+			sinkErr: errors.New("connection refused"),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(events[:2], nil)
+				arg := db.MarkOutboxEventFailedParams{ID: events[0].ID, LastError: "connection refused", MaxAttempts: defaultMaxAttempts}
+				store.EXPECT().MarkOutboxEventFailed(gomock.Any(), gomock.Eq(arg)).Times(1).Return(events[0], nil)
+				store.EXPECT().ReleaseOutboxEvents(gomock.Any(), gomock.Eq([]int64{events[1].ID})).Times(1).Return(nil)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, sink *memorySink, n int, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "connection refused")
+				require.Zero(t, n)
+			},
+		},
+		{
+			// the event failed too many times is dead, it doesn't block the events after it
+			name:    "DeadEvent",
+			sinkErr: errors.New("400 Bad Request"),
+			failID:  events[0].ID,
+			buildStub: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return(events[:2], nil),
+					store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return(nil, nil),
+				)
+
+				dead := events[0]
+				dead.Attempts = defaultMaxAttempts
+				dead.FailedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				arg := db.MarkOutboxEventFailedParams{ID: events[0].ID, LastError: "400 Bad Request", MaxAttempts: defaultMaxAttempts}
+				store.EXPECT().MarkOutboxEventFailed(gomock.Any(), gomock.Eq(arg)).Times(1).Return(dead, nil)
+				store.EXPECT().ReleaseOutboxEvents(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(events[1].ID)).Times(1).Return(events[1], nil)
+			},
+			check: func(t *testing.T, sink *memorySink, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+				require.Len(t, sink.events, 1)
+				require.Equal(t, events[1].ID, sink.events[0].ID)
+			},
+		},
+		{
+			name: "DBError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			check: func(t *testing.T, sink *memorySink, n int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, n)
+				require.Empty(t, sink.events)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			sink := &memorySink{err: tc.sinkErr, failID: tc.failID}
+			relay := NewRelay(store, []Sink{sink}, time.Minute)
+			relay.batchSize = 2
+
+			n, err := relay.PublishPending(context.Background())
+			tc.check(t, sink, n, err)
+		})
+	}
+}
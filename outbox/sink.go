@@ -0,0 +1,289 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// Event is the message published to the sinks
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func newEvent(event db.OutboxEvents) Event {
+	return Event{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+// Sink delivers the events to a downstream system
+// the same event may be published more than once, the receiver can skip the duplicated ones by ID.
+type Sink interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+// sinkTimeout is the time limit of publishing an event over the network
+const sinkTimeout = 10 * time.Second
+
+// NewSink creates a sink by the spec:
+// stdout                        - one JSON line per event to the standard output, mixed with the server log
+// file:/var/log/events.jsonl    - one JSON line per event appended to the file
+// redis://localhost:6379/events - PUBLISH to the channel of a redis server, or any server speaking its protocol
+// http://... or https://...     - POST each event to the webhook
+func NewSink(spec string) (Sink, error) {
+	if spec == "stdout" {
+		return NewWriterSink(os.Stdout), nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink %q: %w", spec, err)
+	}
+	switch u.Scheme {
+	case "file":
+		path := u.Path
+		if path == "" {
+			path = u.Opaque
+		}
+		return NewFileSink(path)
+	case "redis":
+		channel := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || channel == "" {
+			return nil, fmt.Errorf("invalid sink %q: redis://host:port/channel is required", spec)
+		}
+		return NewRedisSink(u.Host, channel), nil
+	case "http", "https":
+		return NewWebhookSink(spec), nil
+	}
+	return nil, fmt.Errorf("invalid sink %q: unknown type", spec)
+}
+
+// NewSinks creates the sinks of the comma separated specs, see NewSink
+func NewSinks(specs string) ([]Sink, error) {
+	var sinks []Sink
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		sink, err := NewSink(spec)
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// WriterSink writes one JSON line per event to the writer
+type WriterSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// NewWriterSink creates a new WriterSink, the writer is not closed by the sink
+func NewWriterSink(writer io.Writer) *WriterSink {
+	return &WriterSink{
+		writer: writer,
+	}
+}
+
+// NewFileSink creates a WriterSink which appends the events to the file
+func NewFileSink(path string) (*WriterSink, error) {
+	if path == "" {
+		return nil, errors.New("path of the file sink is empty")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open event file: %w", err)
+	}
+	return &WriterSink{
+		writer: file,
+		closer: file,
+	}, nil
+}
+
+// Publish writes the event as a JSON line
+func (sink *WriterSink) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	_, err = sink.writer.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file of the file sink
+func (sink *WriterSink) Close() error {
+	if sink.closer == nil {
+		return nil
+	}
+	return sink.closer.Close()
+}
+
+// RedisSink publishes the events to a channel with the PUBLISH command of redis
+// only the PUBLISH command is needed, so the protocol (RESP) is written here without a client library.
+// the connection is kept, and dialed again after an error.
+type RedisSink struct {
+	mutex   sync.Mutex
+	address string
+	channel string
+	conn    net.Conn
+	reader  *bufio.Reader
+}
+
+// NewRedisSink creates a new RedisSink, the server is not connected until the first event
+func NewRedisSink(address string, channel string) *RedisSink {
+	return &RedisSink{
+		address: address,
+		channel: channel,
+	}
+}
+
+// Publish sends PUBLISH channel event, and waits for the reply of the server
+func (sink *RedisSink) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	if sink.conn == nil {
+		dialer := net.Dialer{Timeout: sinkTimeout}
+		conn, err := dialer.DialContext(ctx, "tcp", sink.address)
+		if err != nil {
+			return fmt.Errorf("cannot connect to redis: %w", err)
+		}
+		sink.conn = conn
+		sink.reader = bufio.NewReader(conn)
+	}
+
+	err = sink.publish(data)
+	if err != nil {
+		// the state of the connection is unknown, so it is not used again
+		sink.conn.Close()
+		sink.conn = nil
+	}
+	return err
+}
+
+func (sink *RedisSink) publish(data []byte) error {
+	sink.conn.SetDeadline(time.Now().Add(sinkTimeout))
+
+	var command bytes.Buffer
+	command.WriteString("*3\r\n")
+	for _, arg := range [][]byte{[]byte("PUBLISH"), []byte(sink.channel), data} {
+		command.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		command.Write(arg)
+		command.WriteString("\r\n")
+	}
+	if _, err := sink.conn.Write(command.Bytes()); err != nil {
+		return fmt.Errorf("cannot send to redis: %w", err)
+	}
+
+	// the reply is an integer, the number of subscribers which received the message, e.g. ":1\r\n"
+	reply, err := sink.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("cannot read reply of redis: %w", err)
+	}
+	if !strings.HasPrefix(reply, ":") {
+		return fmt.Errorf("redis error: %s", strings.TrimSpace(reply))
+	}
+	return nil
+}
+
+// Close closes the connection
+func (sink *RedisSink) Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	if sink.conn == nil {
+		return nil
+	}
+	err := sink.conn.Close()
+	sink.conn = nil
+	return err
+}
+
+// WebhookSink posts each event as JSON to the URL
+// a response with status code other than 2xx is an error, the event is published again later.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a new WebhookSink
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: sinkTimeout},
+	}
+}
+
+// Publish posts the event, X-Event-ID can be used by the receiver to skip the duplicated events
+func (sink *WebhookSink) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	request.Header.Set("X-Event-Type", event.EventType)
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("cannot post event: %w", err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook returns status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Close does nothing, the connections are managed by http.Client
+func (sink *WebhookSink) Close() error {
+	return nil
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() Event {
+	return Event{
+		ID:            7,
+		AggregateType: "account",
+		AggregateID:   1,
+		EventType:     "account.created",
+		Payload:       json.RawMessage(`{"id":1,"owner":"alice"}`),
+		CreatedAt:     time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	require.NoError(t, sink.Close())
+
+	// one JSON line per event
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	require.Equal(t, testEvent(), event)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	sink, err := NewSink("file:" + path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	require.NoError(t, sink.Close())
+
+	// the file is appended, not truncated
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(data), "\n"))
+}
+
+// fakeRedis accepts one connection, reads the commands in RESP and replies with the reply
+func fakeRedis(t *testing.T, reply string) (address string, commands chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	commands = make(chan []string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			// *3\r\n then $len\r\n arg\r\n for each arg
+			header, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			var n int
+			_, err = fmt.Sscanf(header, "*%d\r\n", &n)
+			if err != nil {
+				return
+			}
+			args := make([]string, n)
+			for i := range args {
+				var size int
+				line, _ := reader.ReadString('\n')
+				fmt.Sscanf(line, "$%d\r\n", &size)
+				arg := make([]byte, size+2)
+				io.ReadFull(reader, arg)
+				args[i] = string(arg[:size])
+			}
+			commands <- args
+			conn.Write([]byte(reply))
+		}
+	}()
+	return listener.Addr().String(), commands
+}
+
+func TestRedisSink(t *testing.T) {
+	address, commands := fakeRedis(t, ":1\r\n")
+
+	sink, err := NewSink("redis://" + address + "/bank-events")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	// the connection is kept for the next event
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+
+	for i := 0; i < 2; i++ {
+		args := <-commands
+		require.Len(t, args, 3)
+		require.Equal(t, "PUBLISH", args[0])
+		require.Equal(t, "bank-events", args[1])
+
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(args[2]), &event))
+		require.Equal(t, testEvent().ID, event.ID)
+	}
+}
+
+func TestRedisSinkError(t *testing.T) {
+	address, _ := fakeRedis(t, "-ERR unknown command\r\n")
+
+	sink := NewRedisSink(address, "bank-events")
+	defer sink.Close()
+	err := sink.Publish(context.Background(), testEvent())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown command")
+
+	// server is not available
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := listener.Addr().String()
+	listener.Close()
+	err = NewRedisSink(closed, "bank-events").Publish(context.Background(), testEvent())
+	require.Error(t, err)
+}
+
+func TestWebhookSink(t *testing.T) {
+	status := http.StatusOK
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "7", r.Header.Get("X-Event-ID"))
+		require.Equal(t, "account.created", r.Header.Get("X-Event-Type"))
+
+		var event Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received = append(received, event)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(server.URL + "/events")
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), testEvent()))
+	require.Len(t, received, 1)
+	require.Equal(t, testEvent(), received[0])
+
+	// the event is published again later if the webhook fails
+	status = http.StatusInternalServerError
+	err = sink.Publish(context.Background(), testEvent())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "500")
+}
+
+func TestNewSinks(t *testing.T) {
+	sinks, err := NewSinks("stdout, http://localhost:8081/events")
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	require.IsType(t, &WriterSink{}, sinks[0])
+	require.IsType(t, &WebhookSink{}, sinks[1])
+
+	sinks, err = NewSinks("")
+	require.NoError(t, err)
+	require.Empty(t, sinks)
+
+	for _, spec := range []string{"kafka://localhost:9092/events", "redis://localhost:6379", "file:"} {
+		_, err := NewSinks(spec)
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// HoldExpirer expires the holds which are not captured before expires_at in the background of the server process
+// the held amount of the expired holds is available again, see db.ExpireHoldsTx.
+type HoldExpirer struct {
+	store    db.Store
+	interval time.Duration
+}
+
+// NewHoldExpirer creates a new HoldExpirer, interval is the time between the checks of expired holds
+func NewHoldExpirer(store db.Store, interval time.Duration) *HoldExpirer {
+	return &HoldExpirer{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the expirer until ctx is done
+func (expirer *HoldExpirer) Start(ctx context.Context) {
+	ticker := time.NewTicker(expirer.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := expirer.ExpireDue(ctx)
+		if err != nil {
+			log.Printf("cannot expire holds: %v", err)
+		}
+		if n > 0 {
+			log.Printf("%d holds expired", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue expires all the holds past expires_at, and returns the number of expired holds
+func (expirer *HoldExpirer) ExpireDue(ctx context.Context) (int, error) {
+	holds, err := expirer.store.ExpireHoldsTx(ctx)
+	return len(holds), err
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireDue(t *testing.T) {
+	testCases := []struct {
+		name      string
+		buildStub func(store *mockdb.MockStore)
+		check     func(t *testing.T, n int, err error)
+	}{
+		{
+			name: "Expired",
+			buildStub: func(store *mockdb.MockStore) {
+				holds := []db.Holds{{ID: 1, Status: db.HoldStatusExpired}, {ID: 2, Status: db.HoldStatusExpired}}
+				store.EXPECT().ExpireHoldsTx(gomock.Any()).Times(1).Return(holds, nil)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			name: "NoneExpired",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ExpireHoldsTx(gomock.Any()).Times(1).Return(nil, nil)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		{
+			name: "DBError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ExpireHoldsTx(gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.Error(t, err)
+				require.Zero(t, n)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			n, err := NewHoldExpirer(store, time.Minute).ExpireDue(context.Background())
+			tc.check(t, n, err)
+		})
+	}
+}
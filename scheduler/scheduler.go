@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// defaultMaxRuns is the max number of runs in one RunDue,
+// it is a safety net for a schedule which is due again right after its run, the rest are run next time.
+const defaultMaxRuns = 1000
+
+// Scheduler runs the due scheduled transfers in the background of the server process
+// every interval, it runs the due schedules one by one until none is due.
+// each schedule is run in its own transaction, so a failed one doesn't roll back the others.
+type Scheduler struct {
+	store    db.Store
+	interval time.Duration
+	maxRuns  int
+	// now is time.Now, it can be replaced in tests
+	now func() time.Time
+}
+
+// NewScheduler creates a new Scheduler, interval is the time between the checks of due schedules
+func NewScheduler(store db.Store, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		interval: interval,
+		maxRuns:  defaultMaxRuns,
+		now:      time.Now,
+	}
+}
+
+// Start runs the scheduler until ctx is done
+func (scheduler *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := scheduler.RunDue(ctx)
+		if err != nil {
+			log.Printf("cannot run scheduled transfers: %v", err)
+		}
+		if n > 0 {
+			log.Printf("%d scheduled transfers run", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs all the schedules which are due now, and returns the number of runs
+// a failed transfer is recorded in the run and doesn't stop the others,
+// but an error of the database stops RunDue, the schedule is retried next time.
+// at most maxRuns are run, the schedules still due are left for the next time.
+func (scheduler *Scheduler) RunDue(ctx context.Context) (int, error) {
+	now := scheduler.now()
+
+	n := 0
+	for ctx.Err() == nil {
+		if n >= scheduler.maxRuns {
+			log.Printf("%d scheduled transfers run, the rest is left for the next time", n)
+			return n, nil
+		}
+
+		result, err := scheduler.store.RunScheduledTransferTx(ctx, now)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+
+		if result.Run.Status == db.ScheduledTransferRunFailed {
+			log.Printf("scheduled transfer [%d] failed: %s", result.ScheduledTransfer.ID, result.Run.Error)
+		}
+	}
+	return n, ctx.Err()
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDue(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		maxRuns   int
+		buildStub func(store *mockdb.MockStore)
+		check     func(t *testing.T, n int, err error)
+	}{
+		{
+			name: "RunUntilNoneDue",
+			buildStub: func(store *mockdb.MockStore) {
+				succeeded := db.RunScheduledTransferTxResult{Run: db.ScheduledTransferRuns{Status: db.ScheduledTransferRunSucceeded}}
+				failed := db.RunScheduledTransferTxResult{Run: db.ScheduledTransferRuns{Status: db.ScheduledTransferRunFailed}}
+				gomock.InOrder(
+					store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).Return(succeeded, nil),
+					store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).Return(failed, nil),
+					store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).
+						Return(db.RunScheduledTransferTxResult{}, db.ErrRecordNotFound),
+				)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			// a schedule which is always due can't keep RunDue running
+			name:    "MaxRuns",
+			maxRuns: 2,
+			buildStub: func(store *mockdb.MockStore) {
+				succeeded := db.RunScheduledTransferTxResult{Run: db.ScheduledTransferRuns{Status: db.ScheduledTransferRunSucceeded}}
+				store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).Times(2).Return(succeeded, nil)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			name: "NoneDue",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).Times(1).
+					Return(db.RunScheduledTransferTxResult{}, db.ErrRecordNotFound)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, n)
+			},
+		},
+		{
+			// the error of database stops the runs, they are retried next time
+			name: "DBError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().RunScheduledTransferTx(gomock.Any(), gomock.Eq(now)).Times(1).
+					Return(db.RunScheduledTransferTxResult{}, errors.New("connection refused"))
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.Error(t, err)
+				require.Zero(t, n)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			scheduler := NewScheduler(store, time.Minute)
+			scheduler.now = func() time.Time { return now }
+			if tc.maxRuns > 0 {
+				scheduler.maxRuns = tc.maxRuns
+			}
+
+			n, err := scheduler.RunDue(context.Background())
+			tc.check(t, n, err)
+		})
+	}
+}
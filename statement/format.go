@@ -0,0 +1,192 @@
+package statement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bank-demo/currency"
+)
+
+// file formats of the statement
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatOFX  = "ofx"
+)
+
+// ContentType returns the MIME type of the format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/json"
+	}
+}
+
+// Write writes the statement in the format, JSON is used if the format is unknown
+func (statement Statement) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return statement.WriteCSV(w)
+	case FormatOFX:
+		return statement.WriteOFX(w)
+	default:
+		return statement.WriteJSON(w)
+	}
+}
+
+// WriteJSON writes the statement as a JSON object
+func (statement Statement) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statement)
+}
+
+// dateFormat is the format of the dates in CSV
+const dateFormat = "2006-01-02"
+
+// WriteCSV writes the statement as CSV, amounts are decimal numbers in the currency of the account
+// the first line after the header is the opening balance, and the last line is the closing balance.
+func (statement Statement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	code := statement.Currency
+
+	records := [][]string{
+		{"date", "entry_id", "description", "amount", "balance", "currency"},
+		{statement.From.Format(dateFormat), "", "Opening balance", "", currency.Format(statement.OpeningBalance, code), code},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.Time.Format(dateFormat),
+			strconv.FormatInt(line.EntryID, 10),
+			description(line.Amount),
+			line.FormattedAmount,
+			line.FormattedBalance,
+			code,
+		})
+	}
+	records = append(records, []string{
+		statement.To.Format(dateFormat), "", "Closing balance", "", currency.Format(statement.ClosingBalance, code), code,
+	})
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("cannot write statement csv: %w", err)
+	}
+	return nil
+}
+
+func description(amount int64) string {
+	if amount > 0 {
+		return "Credit"
+	}
+	return "Debit"
+}
+
+// OFX (Open Financial Exchange) 2.2 is an XML format which can be imported by most accounting tools.
+// the file has the sign on response required by the spec, and the bank statement response.
+
+// ofxBankID is the routing number of the bank in the OFX file
+const ofxBankID = "BANKDEMO"
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FITID  string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Status   ofxStatus `xml:"SONRS>STATUS"`
+		Server   string    `xml:"SONRS>DTSERVER"`
+		Language string    `xml:"SONRS>LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Statement struct {
+		TransactionID string           `xml:"TRNUID"`
+		Status        ofxStatus        `xml:"STATUS"`
+		Currency      string           `xml:"STMTRS>CURDEF"`
+		BankID        string           `xml:"STMTRS>BANKACCTFROM>BANKID"`
+		AccountID     string           `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+		AccountType   string           `xml:"STMTRS>BANKACCTFROM>ACCTTYPE"`
+		Start         string           `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+		End           string           `xml:"STMTRS>BANKTRANLIST>DTEND"`
+		Transactions  []ofxTransaction `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+		LedgerBalance ofxBalance       `xml:"STMTRS>LEDGERBAL"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+// ofxTime formats the time in the OFX datetime format, e.g. 20211001093000[0:GMT]
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// WriteOFX writes the statement as an OFX 2.2 file
+func (statement Statement) WriteOFX(w io.Writer) error {
+	code := statement.Currency
+	end := statement.To.AddDate(0, 0, 1)
+
+	var doc ofxDocument
+	doc.SignOn.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Server = ofxTime(statement.GeneratedAt)
+	doc.SignOn.Language = "ENG"
+
+	doc.Statement.TransactionID = "0"
+	doc.Statement.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.Statement.Currency = code
+	doc.Statement.BankID = ofxBankID
+	doc.Statement.AccountID = strconv.FormatInt(statement.AccountID, 10)
+	doc.Statement.AccountType = strings.ToUpper(statement.AccountType)
+	doc.Statement.Start = ofxTime(statement.From)
+	doc.Statement.End = ofxTime(end)
+	for _, line := range statement.Lines {
+		trnType := "CREDIT"
+		if line.Amount < 0 {
+			trnType = "DEBIT"
+		}
+		doc.Statement.Transactions = append(doc.Statement.Transactions, ofxTransaction{
+			Type:   trnType,
+			Posted: ofxTime(line.Time),
+			Amount: line.FormattedAmount,
+			// FITID is the unique ID of the transaction, the tools use it to skip the imported ones
+			FITID: strconv.FormatInt(line.EntryID, 10),
+			Name:  description(line.Amount),
+		})
+	}
+	doc.Statement.LedgerBalance = ofxBalance{
+		Amount: currency.Format(statement.ClosingBalance, code),
+		AsOf:   ofxTime(end),
+	}
+
+	header := xml.Header +
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return fmt.Errorf("cannot write statement ofx: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("cannot write statement ofx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package statement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bank-demo/currency"
+	db "github.com/bank-demo/db/sqlc"
+)
+
+// a statement lists the entries of an account in a date range with the running balance.
+// the balance of an account is always the sum of its entries,
+// so the opening balance is the sum of the entries before the range,
+// and the closing balance is the opening balance plus the entries in the range.
+
+// Line is an entry of the statement, Balance is the running balance after the entry
+type Line struct {
+	EntryID          int64     `json:"entry_id"`
+	Time             time.Time `json:"time"`
+	Amount           int64     `json:"amount"`
+	FormattedAmount  string    `json:"formatted_amount"`
+	Balance          int64     `json:"balance"`
+	FormattedBalance string    `json:"formatted_balance"`
+}
+
+// Statement is the statement of an account
+// From and To are dates in UTC, both days are included.
+type Statement struct {
+	AccountID               int64     `json:"account_id"`
+	Owner                   string    `json:"owner"`
+	AccountType             string    `json:"account_type"`
+	Currency                string    `json:"currency"`
+	From                    time.Time `json:"from"`
+	To                      time.Time `json:"to"`
+	OpeningBalance          int64     `json:"opening_balance"`
+	FormattedOpeningBalance string    `json:"formatted_opening_balance"`
+	ClosingBalance          int64     `json:"closing_balance"`
+	FormattedClosingBalance string    `json:"formatted_closing_balance"`
+	TotalCredits            int64     `json:"total_credits"`
+	TotalDebits             int64     `json:"total_debits"`
+	Lines                   []Line    `json:"lines"`
+	GeneratedAt             time.Time `json:"generated_at"`
+}
+
+// Build creates the statement of the account from the date from to the date to
+// the queries run in one read-only repeatable read transaction,
+// so the opening balance and the entries are a consistent snapshot even if transfers are made at the same time.
+func Build(ctx context.Context, store db.Store, account db.Accounts, from time.Time, to time.Time) (Statement, error) {
+	from = truncateDate(from)
+	to = truncateDate(to)
+	statement := Statement{
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		AccountType: account.Type,
+		Currency:    account.Currency,
+		From:        from,
+		To:          to,
+	}
+
+	var entries []db.Entries
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.ExecTx(ctx, opts, func(q db.Querier) error {
+		var err error
+
+		statement.GeneratedAt = time.Now().UTC()
+		statement.OpeningBalance, err = q.GetAccountBalanceBefore(ctx, db.GetAccountBalanceBeforeParams{
+			AccountID: account.ID,
+			Before:    from,
+		})
+		if err != nil {
+			return err
+		}
+
+		// to is included, so the end time is the beginning of the next day
+		entries, err = q.ListEntriesBetween(ctx, db.ListEntriesBetweenParams{
+			AccountID: account.ID,
+			StartTime: from,
+			EndTime:   to.AddDate(0, 0, 1),
+		})
+		return err
+	})
+	if err != nil {
+		return Statement{}, err
+	}
+
+	balance := statement.OpeningBalance
+	statement.Lines = make([]Line, len(entries))
+	for i, entry := range entries {
+		balance += entry.Amount
+		if entry.Amount > 0 {
+			statement.TotalCredits += entry.Amount
+		} else {
+			statement.TotalDebits -= entry.Amount
+		}
+		statement.Lines[i] = Line{
+			EntryID:          entry.ID,
+			Time:             entry.CreatedAt.UTC(),
+			Amount:           entry.Amount,
+			FormattedAmount:  currency.Format(entry.Amount, account.Currency),
+			Balance:          balance,
+			FormattedBalance: currency.Format(balance, account.Currency),
+		}
+	}
+	statement.ClosingBalance = balance
+	statement.FormattedOpeningBalance = currency.Format(statement.OpeningBalance, account.Currency)
+	statement.FormattedClosingBalance = currency.Format(statement.ClosingBalance, account.Currency)
+	return statement, nil
+}
+
+func truncateDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bank-demo/db/mock"
+	db "github.com/bank-demo/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAccount = db.Accounts{ID: 7, Owner: "alice", Currency: "USD", Type: db.AccountTypeChecking}
+	testFrom    = time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	testTo      = time.Date(2021, 10, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// buildTestStatement builds the statement of testAccount with the opening balance and the entries in the mock store
+func buildTestStatement(t *testing.T, opening int64, entries []db.Entries) Statement {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	// the mock store is also a Querier, so fn runs with the mock as the transaction
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, opts *sql.TxOptions, fn func(db.Querier) error) error {
+			require.True(t, opts.ReadOnly)
+			return fn(store)
+		})
+	store.EXPECT().GetAccountBalanceBefore(gomock.Any(), gomock.Eq(db.GetAccountBalanceBeforeParams{
+		AccountID: testAccount.ID,
+		Before:    testFrom,
+	})).Times(1).Return(opening, nil)
+	// to is included
+	store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Eq(db.ListEntriesBetweenParams{
+		AccountID: testAccount.ID,
+		StartTime: testFrom,
+		EndTime:   testTo.AddDate(0, 0, 1),
+	})).Times(1).Return(entries, nil)
+
+	statement, err := Build(context.Background(), store, testAccount, testFrom, testTo)
+	require.NoError(t, err)
+	return statement
+}
+
+func testEntries() []db.Entries {
+	return []db.Entries{
+		{ID: 1, AccountID: testAccount.ID, Amount: 2500, CreatedAt: testFrom.Add(time.Hour)},
+		{ID: 2, AccountID: testAccount.ID, Amount: -1000, CreatedAt: testFrom.Add(48 * time.Hour)},
+		{ID: 3, AccountID: testAccount.ID, Amount: -250, CreatedAt: testTo.Add(23 * time.Hour)},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	statement := buildTestStatement(t, 10000, testEntries())
+
+	require.Equal(t, testAccount.ID, statement.AccountID)
+	require.Equal(t, "USD", statement.Currency)
+	require.Equal(t, int64(10000), statement.OpeningBalance)
+	require.Equal(t, "100.00", statement.FormattedOpeningBalance)
+	require.Equal(t, int64(11250), statement.ClosingBalance)
+	require.Equal(t, "112.50", statement.FormattedClosingBalance)
+	require.Equal(t, int64(2500), statement.TotalCredits)
+	require.Equal(t, int64(1250), statement.TotalDebits)
+
+	// running balance after each entry
+	require.Len(t, statement.Lines, 3)
+	balances := []int64{12500, 11500, 11250}
+	for i, line := range statement.Lines {
+		require.Equal(t, balances[i], line.Balance)
+	}
+	require.Equal(t, "-10.00", statement.Lines[1].FormattedAmount)
+
+	// no entry in the range
+	statement = buildTestStatement(t, 10000, nil)
+	require.Equal(t, statement.OpeningBalance, statement.ClosingBalance)
+	require.NotNil(t, statement.Lines)
+}
+
+func TestWriteCSV(t *testing.T) {
+	statement := buildTestStatement(t, 10000, testEntries())
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, FormatCSV))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	// header, opening balance, 3 entries, closing balance
+	require.Len(t, records, 6)
+	require.Equal(t, []string{"2021-10-01", "", "Opening balance", "", "100.00", "USD"}, records[1])
+	require.Equal(t, []string{"2021-10-03", "2", "Debit", "-10.00", "115.00", "USD"}, records[3])
+	require.Equal(t, []string{"2021-10-31", "", "Closing balance", "", "112.50", "USD"}, records[5])
+}
+
+func TestWriteJSON(t *testing.T) {
+	statement := buildTestStatement(t, 10000, testEntries())
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, FormatJSON))
+
+	var decoded Statement
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, statement.ClosingBalance, decoded.ClosingBalance)
+	require.Equal(t, statement.Lines, decoded.Lines)
+}
+
+func TestWriteOFX(t *testing.T) {
+	statement := buildTestStatement(t, 10000, testEntries())
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, FormatOFX))
+	require.True(t, strings.Contains(buf.String(), `OFXHEADER="200"`))
+
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "USD", doc.Statement.Currency)
+	require.Equal(t, "7", doc.Statement.AccountID)
+	require.Equal(t, "CHECKING", doc.Statement.AccountType)
+	require.Equal(t, "20211001000000[0:GMT]", doc.Statement.Start)
+	require.Equal(t, "20211101000000[0:GMT]", doc.Statement.End)
+	require.Equal(t, "112.50", doc.Statement.LedgerBalance.Amount)
+
+	require.Len(t, doc.Statement.Transactions, 3)
+	require.Equal(t, ofxTransaction{
+		Type:   "CREDIT",
+		Posted: "20211001010000[0:GMT]",
+		Amount: "25.00",
+		FITID:  "1",
+		Name:   "Credit",
+	}, doc.Statement.Transactions[0])
+	require.Equal(t, "DEBIT", doc.Statement.Transactions[1].Type)
+}
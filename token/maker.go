@@ -0,0 +1,16 @@
+package token
+
+import "time"
+
+// Maker is an interface for managing tokens
+// the server doesn't care which kind of token is used,
+// so both JWT and PASETO implement this interface, and we can switch between them easily.
+type Maker interface {
+	// CreateToken creates a new token for a specific username, token type and duration
+	// the payload is returned too, its ID and ExpiredAt are stored in the session of refresh token
+	CreateToken(username string, tokenType TokenType, duration time.Duration) (string, *Payload, error)
+
+	// VerifyToken checks if the token is valid or not
+	// if it is valid, return the payload data stored inside the body of the token
+	VerifyToken(token string) (*Payload, error)
+}
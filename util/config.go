@@ -1,6 +1,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -10,6 +12,31 @@ type Config struct {
 	DBDriver      string `mapstructure:"DB_DRIVER"`
 	DBSource      string `mapstructure:"DB_SOURCE"`
 	ServerAddress string `mapstructure:"Server_Address"`
+	// TokenSymmetricKey is used by token maker to sign/encrypt the access token
+	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	// RefreshTokenDuration is much longer than access token,
+	// client uses refresh token to renew the access token
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	// MaxPageSize is the max number of records in one page of the list APIs
+	MaxPageSize int32 `mapstructure:"MAX_PAGE_SIZE"`
+	// ExchangeRatesFile is the JSON file of exchange rates for cross-currency transfers,
+	// the rates in the exchange_rates table are used if it is empty.
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+	// SchedulerInterval is the time between the checks of due scheduled transfers,
+	// the scheduler is not started in the server process if it is 0.
+	SchedulerInterval time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	// HoldExpiryInterval is the time between the checks of expired holds,
+	// the holds are not expired in the server process if it is 0.
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	// FeeScheduleFile is the JSON file of the fee rules of transfers,
+	// the rules in the fee_rules table are used if it is empty.
+	FeeScheduleFile string `mapstructure:"FEE_SCHEDULE_FILE"`
+	// OutboxInterval is the time between the checks of unpublished outbox events,
+	// the relay is not started in the server process if it is 0.
+	OutboxInterval time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	// OutboxSinks is the comma separated sinks of the outbox events, e.g. "file:outbox_events.jsonl,https://example.com/hook"
+	OutboxSinks string `mapstructure:"OUTBOX_SINKS"`
 }
 
 // In order to get the value of the variables and store them in this struct,
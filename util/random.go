@@ -1,9 +1,12 @@
 package util
 
 import (
+	"fmt"
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/bank-demo/currency"
 )
 
 const alphabet = "abcdefghijklmnopqrstuvxyz"
@@ -55,7 +58,12 @@ func RandomMoney() int64 {
 
 // RandomCurrency generates a random currency code
 func RandomCurrency() string {
-	currencies := []string{"USD", "TWD"}
+	currencies := currency.Codes()
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
+
+// RandomEmail generates a random email
+func RandomEmail() string {
+	return fmt.Sprintf("%s@email.com", RandomString(6))
+}